@@ -0,0 +1,53 @@
+package fetch
+
+import (
+	"encoding/xml"
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"github.com/openanno/bget/api/types"
+)
+
+// ArxivAPIHost is the arXiv API
+const ArxivAPIHost = "http://export.arxiv.org/api/query"
+
+// Arxiv query entries of arXiv IDs via http://export.arxiv.org/api/query
+func Arxiv(ids []string, bapiClis *types.BapiClisT) (entries []types.ArxivEntry, err error) {
+	for i := 0; i < len(ids); i += 100 {
+		end := i + 100
+		if end > len(ids) {
+			end = len(ids)
+		}
+		url := fmt.Sprintf("%s?id_list=%s&max_results=%d", ArxivAPIHost,
+			neturl.QueryEscape(strings.Join(ids[i:end], ",")), end-i)
+		buf, err := getBytes("arXiv", url, bapiClis)
+		if err != nil {
+			return entries, err
+		}
+		feed := types.ArxivFeed{}
+		if err = xml.Unmarshal(buf, &feed); err != nil {
+			return entries, err
+		}
+		for _, v := range feed.Entries {
+			// arXiv returns an error entry for invalid ids
+			if strings.Contains(v.ID, "arxiv.org/api/errors") {
+				continue
+			}
+			entries = append(entries, v)
+		}
+	}
+	return entries, nil
+}
+
+// ArxivID return arXiv ID (without version) from entry ID
+func ArxivID(entryID string) string {
+	id := entryID
+	if i := strings.Index(id, "/abs/"); i >= 0 {
+		id = id[i+len("/abs/"):]
+	}
+	if i := strings.LastIndex(id, "v"); i > 0 && strings.Trim(id[i+1:], "0123456789") == "" {
+		id = id[0:i]
+	}
+	return id
+}
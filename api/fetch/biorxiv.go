@@ -0,0 +1,26 @@
+package fetch
+
+import (
+	"fmt"
+
+	"github.com/openanno/bget/api/types"
+)
+
+// BiorxivAPIHost is the bioRxiv/medRxiv API
+const BiorxivAPIHost = "https://api.biorxiv.org"
+
+// Biorxiv query all versions of a preprint via https://api.biorxiv.org/details,
+// server is biorxiv or medrxiv
+func Biorxiv(server string, doi string, bapiClis *types.BapiClisT) (versions []types.BiorxivVersion, err error) {
+	url := fmt.Sprintf("%s/details/%s/%s/na/json", BiorxivAPIHost, server, doi)
+	ret := types.BiorxivDetailsRet{}
+	if err = getJSON(server, url, bapiClis, &ret); err != nil {
+		return versions, err
+	}
+	for _, v := range ret.Collection {
+		if v.Doi != "" {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
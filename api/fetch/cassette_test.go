@@ -0,0 +1,41 @@
+package fetch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/openanno/bget/api/types"
+	"github.com/openanno/bget/cassette"
+)
+
+func useCassette(t *testing.T, name string) {
+	if err := cassette.Start(cassette.ModeReplay, cassette.Path(name)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNcbi(t *testing.T) {
+	useCassette(t, "ncbi")
+	defer cassette.Stop()
+	var buf bytes.Buffer
+	bapiClis := &types.BapiClisT{Query: "30487223[pmid]", Timeout: 5, From: -1, Size: -1, Format: "xml"}
+	Ncbi(bapiClis, &types.NcbiClisT{NcbiDB: "pubmed", NcbiRetmax: 100}, &buf)
+	if !strings.Contains(buf.String(), "<PMID Version=\"1\">30487223</PMID>") {
+		t.Errorf("unexpected records: %s", buf.String())
+	}
+}
+
+func TestGdc(t *testing.T) {
+	useCassette(t, "gdc")
+	defer cassette.Stop()
+	var buf bytes.Buffer
+	endpoint := &types.GdcEndpoints{Status: true}
+	endpoint.ExtraParams.From = -1
+	endpoint.ExtraParams.Size = -1
+	endpoint.ExtraParams.Format = "json"
+	Gdc(endpoint, &types.BapiClisT{Timeout: 5}, &buf)
+	if !strings.Contains(buf.String(), `"data_release":"Data Release 23.0`) {
+		t.Errorf("unexpected status: %s", buf.String())
+	}
+}
@@ -0,0 +1,74 @@
+package fetch
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"github.com/openanno/bget/api/types"
+)
+
+// OpenCitationsAPIHost is the OpenCitations COCI API
+const OpenCitationsAPIHost = "https://opencitations.net/index/coci/api/v1"
+
+// OpenAlexAPIHost is the OpenAlex API
+const OpenAlexAPIHost = "https://api.openalex.org"
+
+// OpenCitationsCitedBy return the DOIs citing doi via OpenCitations
+func OpenCitationsCitedBy(doi string, bapiClis *types.BapiClisT) (dois []string, err error) {
+	url := fmt.Sprintf("%s/citations/%s", OpenCitationsAPIHost, doi)
+	ret := []types.OpenCitationsCitation{}
+	if err = getJSON("OpenCitations", url, bapiClis, &ret); err != nil {
+		return dois, err
+	}
+	for _, v := range ret {
+		// the citing field may be prefixed with 'coci => '
+		citing := strings.TrimSpace(v.Citing)
+		if i := strings.LastIndex(citing, "=> "); i >= 0 {
+			citing = citing[i+len("=> "):]
+		}
+		if citing != "" {
+			dois = append(dois, citing)
+		}
+	}
+	return dois, nil
+}
+
+// OpenAlexCitedBy return the DOIs citing doi via OpenAlex, max limits
+// the number of returned DOIs (<= 0 for all)
+func OpenAlexCitedBy(doi string, max int, bapiClis *types.BapiClisT) (dois []string, err error) {
+	work := types.OpenAlexWork{}
+	url := fmt.Sprintf("%s/works/doi:%s", OpenAlexAPIHost, doi)
+	if bapiClis.Email != "" {
+		url = url + "?mailto=" + neturl.QueryEscape(bapiClis.Email)
+	}
+	if err = getJSON("OpenAlex", url, bapiClis, &work); err != nil {
+		return dois, err
+	}
+	if work.ID == "" {
+		return dois, fmt.Errorf("%s not found in OpenAlex", doi)
+	}
+	id := work.ID[strings.LastIndex(work.ID, "/")+1:]
+	cursor := "*"
+	for cursor != "" {
+		url = fmt.Sprintf("%s/works?filter=cites:%s&per-page=200&select=id,doi&cursor=%s", OpenAlexAPIHost, id,
+			neturl.QueryEscape(cursor))
+		if bapiClis.Email != "" {
+			url = url + "&mailto=" + neturl.QueryEscape(bapiClis.Email)
+		}
+		ret := types.OpenAlexWorksRet{}
+		if err = getJSON("OpenAlex", url, bapiClis, &ret); err != nil {
+			return dois, err
+		}
+		for _, v := range ret.Results {
+			if v.Doi != "" {
+				dois = append(dois, strings.TrimPrefix(v.Doi, "https://doi.org/"))
+			}
+		}
+		if len(ret.Results) == 0 || (max > 0 && len(dois) >= max) {
+			break
+		}
+		cursor = ret.Meta.NextCursor
+	}
+	return dois, nil
+}
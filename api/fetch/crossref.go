@@ -2,21 +2,26 @@ package fetch
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	neturl "net/url"
 	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/openanno/bget/api/types"
+	"github.com/openanno/bget/cassette"
 	cio "github.com/openbiox/ligo/io"
-	cnet "github.com/openbiox/ligo/net"
 	"github.com/openbiox/ligo/stringo"
 )
 
 const CrossRefHost = "https://www.crossref.org/guestquery"
 
+// CrossRefAPIHost is the CrossRef REST API
+const CrossRefAPIHost = "https://api.crossref.org"
+
 // CrossRef access https://clinicaltrials.gov API
 func CrossRef(endpoints *types.CrossRefEndpoints, bapiClis *types.BapiClisT, f func(), of io.Writer) bool {
 	setLog(bapiClis)
@@ -30,7 +35,7 @@ func CrossRef(endpoints *types.CrossRefEndpoints, bapiClis *types.BapiClisT, f f
 	f()
 	data, bdr := setCrossRefQueryData(endpoints, bapiClis)
 	method := "POST"
-	client := cnet.NewHTTPClient(bapiClis.Timeout, bapiClis.Proxy)
+	client := cassette.NewHTTPClient(bapiClis.Timeout, bapiClis.Proxy)
 	req, err := http.NewRequest(method, CrossRefHost,
 		&data)
 	//req.Header.Add("Accept-Encoding", "gzip, deflate, br")
@@ -94,3 +99,102 @@ func setCrossRefQueryData(endpoints *types.CrossRefEndpoints, bapiClis *types.Ba
 	w.Close()
 	return b, w.Boundary()
 }
+
+// CrossRefWork access https://api.crossref.org/works/{doi} API
+func CrossRefWork(doi string, bapiClis *types.BapiClisT) (*types.CrossRefWork, error) {
+	if doi == "" {
+		return nil, fmt.Errorf("doi is required")
+	}
+	url := fmt.Sprintf("%s/works/%s", CrossRefAPIHost, neturl.PathEscape(doi))
+	if bapiClis.Email != "" {
+		url = url + "?mailto=" + neturl.QueryEscape(bapiClis.Email)
+	}
+	ret := types.CrossRefWorkRet{}
+	if err := getJSON("Crossref", url, bapiClis, &ret); err != nil {
+		return nil, err
+	}
+	if ret.Status != "ok" {
+		return nil, fmt.Errorf("crossref returns %s for %s", ret.Status, doi)
+	}
+	return &ret.Message, nil
+}
+
+// CrossRefQuery search https://api.crossref.org/works using bibliographic query
+func CrossRefQuery(bibliographic string, filter string, rows int, bapiClis *types.BapiClisT) ([]types.CrossRefWork, error) {
+	params := neturl.Values{}
+	params.Set("query.bibliographic", bibliographic)
+	params.Set("rows", fmt.Sprintf("%d", rows))
+	if filter != "" {
+		params.Set("filter", filter)
+	}
+	if bapiClis.Email != "" {
+		params.Set("mailto", bapiClis.Email)
+	}
+	url := fmt.Sprintf("%s/works?%s", CrossRefAPIHost, params.Encode())
+	ret := types.CrossRefWorksRet{}
+	if err := getJSON("Crossref", url, bapiClis, &ret); err != nil {
+		return nil, err
+	}
+	if ret.Status != "ok" {
+		return nil, fmt.Errorf("crossref returns %s for %s", ret.Status, bibliographic)
+	}
+	return ret.Message.Items, nil
+}
+
+// CrossRefUpdates return the notices (retractions, corrections, etc.) that
+// update the DOI via https://api.crossref.org/works?filter=updates:{doi}
+func CrossRefUpdates(doi string, bapiClis *types.BapiClisT) ([]types.CrossRefWork, error) {
+	params := neturl.Values{}
+	params.Set("filter", "updates:"+doi)
+	params.Set("rows", "50")
+	if bapiClis.Email != "" {
+		params.Set("mailto", bapiClis.Email)
+	}
+	url := fmt.Sprintf("%s/works?%s", CrossRefAPIHost, params.Encode())
+	ret := types.CrossRefWorksRet{}
+	if err := getJSON("Crossref", url, bapiClis, &ret); err != nil {
+		return nil, err
+	}
+	if ret.Status != "ok" {
+		return nil, fmt.Errorf("crossref returns %s for updates of %s", ret.Status, doi)
+	}
+	return ret.Message.Items, nil
+}
+
+// CrossRefJournalWorks enumerate https://api.crossref.org/journals/{issn}/works
+// with cursor paging, paging stops at max works or once stop returns true
+func CrossRefJournalWorks(issn string, filter string, sort string, max int, bapiClis *types.BapiClisT,
+	stop func(items []types.CrossRefWork) bool) (works []types.CrossRefWork, err error) {
+	cursor := "*"
+	for {
+		params := neturl.Values{}
+		params.Set("rows", "1000")
+		params.Set("cursor", cursor)
+		if filter != "" {
+			params.Set("filter", filter)
+		}
+		if sort != "" {
+			params.Set("sort", sort)
+			params.Set("order", "desc")
+		}
+		if bapiClis.Email != "" {
+			params.Set("mailto", bapiClis.Email)
+		}
+		url := fmt.Sprintf("%s/journals/%s/works?%s", CrossRefAPIHost, neturl.PathEscape(issn), params.Encode())
+		ret := types.CrossRefWorksRet{}
+		if err = getJSON("Crossref", url, bapiClis, &ret); err != nil {
+			return works, err
+		}
+		if ret.Status != "ok" {
+			return works, fmt.Errorf("crossref returns %s for journal %s", ret.Status, issn)
+		}
+		works = append(works, ret.Message.Items...)
+		if max > 0 && len(works) >= max {
+			return works[0:max], nil
+		}
+		if (stop != nil && stop(ret.Message.Items)) || len(ret.Message.Items) == 0 || ret.Message.NextCursor == "" {
+			return works, nil
+		}
+		cursor = ret.Message.NextCursor
+	}
+}
@@ -0,0 +1,262 @@
+package fetch
+
+import (
+	"encoding/json"
+	"fmt"
+	neturl "net/url"
+	"strconv"
+	"strings"
+
+	"github.com/openanno/bget/api/types"
+)
+
+// ZenodoAPIHost is the Zenodo REST API
+const ZenodoAPIHost = "https://zenodo.org/api"
+
+// FigshareAPIHost is the Figshare v2 API
+const FigshareAPIHost = "https://api.figshare.com/v2"
+
+// DryadAPIHost is the Dryad v2 API
+const DryadAPIHost = "https://datadryad.org"
+
+// OsfAPIHost is the OSF v2 API
+const OsfAPIHost = "https://api.osf.io/v2"
+
+func getRawJSON(siteName, url string, bapiClis *types.BapiClisT, v interface{}) (json.RawMessage, error) {
+	buf, err := getBytes(siteName, url, bapiClis)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(buf, v); err != nil {
+		return nil, fmt.Errorf("%s: %v", url, err)
+	}
+	return json.RawMessage(buf), nil
+}
+
+// ZenodoDataset list the files of Zenodo record, version latest
+// query the latest version of the record, other versions are resolved
+// via the version list
+func ZenodoDataset(id string, version string, bapiClis *types.BapiClisT) (*types.DatasetRecord, error) {
+	url := fmt.Sprintf("%s/records/%s", ZenodoAPIHost, id)
+	if version == "latest" {
+		url = url + "/versions/latest"
+	} else if version != "" {
+		versionID, err := zenodoVersionID(id, version, bapiClis)
+		if err != nil {
+			return nil, err
+		}
+		url = fmt.Sprintf("%s/records/%s", ZenodoAPIHost, versionID)
+	}
+	record := types.ZenodoRecord{}
+	raw, err := getRawJSON("Zenodo", url, bapiClis, &record)
+	if err != nil {
+		return nil, err
+	}
+	ret := &types.DatasetRecord{Repository: "zenodo", ID: record.ID.String(), Doi: record.Doi,
+		Version: record.Metadata.Version, Title: record.Metadata.Title, Record: raw}
+	files := types.ZenodoFilesRet{}
+	if err := getJSON("Zenodo", fmt.Sprintf("%s/records/%s/files", ZenodoAPIHost, ret.ID), bapiClis, &files); err == nil && len(files.Entries) > 0 {
+		record.Files = files.Entries
+	}
+	for _, f := range record.Files {
+		name := f.Key
+		if name == "" {
+			name = f.Filename
+		}
+		size := f.Size
+		if size == 0 {
+			size = f.Filesize
+		}
+		checksum := f.Checksum
+		if checksum != "" && !strings.Contains(checksum, ":") {
+			checksum = "md5:" + checksum
+		}
+		ret.Files = append(ret.Files, types.DatasetFile{
+			Path:     name,
+			URL:      fmt.Sprintf("https://zenodo.org/records/%s/files/%s?download=1", ret.ID, neturl.PathEscape(name)),
+			Size:     size,
+			Checksum: checksum,
+		})
+	}
+	return ret, nil
+}
+
+// zenodoVersionID return the record ID of version in the versions of record id
+func zenodoVersionID(id string, version string, bapiClis *types.BapiClisT) (string, error) {
+	var records []types.ZenodoRecord
+	for page := 1; ; page++ {
+		ret := types.ZenodoVersionsRet{}
+		if err := getJSON("Zenodo", fmt.Sprintf("%s/records/%s/versions?size=100&page=%d", ZenodoAPIHost, id, page), bapiClis, &ret); err != nil {
+			return "", err
+		}
+		records = append(records, ret.Hits.Hits...)
+		if len(ret.Hits.Hits) == 0 || len(records) >= ret.Hits.Total {
+			break
+		}
+	}
+	if versionID := ZenodoVersion(records, version); versionID != "" {
+		return versionID, nil
+	}
+	return "", fmt.Errorf("version %s of Zenodo record %s not found in %d versions", version, id, len(records))
+}
+
+// ZenodoVersion return the record ID of version in records, version is
+// matched with the version of metadata (e.g. v1.2), or the 1-based index
+// of the version if numeric
+func ZenodoVersion(records []types.ZenodoRecord, version string) string {
+	norm := func(s string) string {
+		return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "v")
+	}
+	for _, r := range records {
+		if r.Metadata.Version != "" && norm(r.Metadata.Version) == norm(version) {
+			return r.ID.String()
+		}
+	}
+	if index, err := strconv.Atoi(version); err == nil {
+		for _, r := range records {
+			if v := r.Metadata.Relations.Version; len(v) > 0 && v[0].Index+1 == index {
+				return r.ID.String()
+			}
+		}
+	}
+	return ""
+}
+
+// FigshareDataset list the files of Figshare article, version is the
+// version number or latest
+func FigshareDataset(id string, version string, bapiClis *types.BapiClisT) (*types.DatasetRecord, error) {
+	url := fmt.Sprintf("%s/articles/%s", FigshareAPIHost, id)
+	if version != "" && version != "latest" {
+		url = fmt.Sprintf("%s/versions/%s", url, version)
+	}
+	article := types.FigshareArticle{}
+	raw, err := getRawJSON("Figshare", url, bapiClis, &article)
+	if err != nil {
+		return nil, err
+	}
+	ret := &types.DatasetRecord{Repository: "figshare", ID: id, Doi: article.Doi,
+		Version: strconv.Itoa(article.Version), Title: article.Title, Record: raw}
+	if version != "" && version != "latest" {
+		// the files of versioned article belong to that version, while
+		// /articles/{id}/files always lists the latest version
+		ret.Files = figshareFiles(article.Files)
+		return ret, nil
+	}
+	// files are paginated, at most 1000 per page
+	for page := 1; ; page++ {
+		files := []types.FigshareFile{}
+		if err := getJSON("Figshare", fmt.Sprintf("%s/articles/%s/files?page=%d&page_size=1000", FigshareAPIHost, id, page), bapiClis, &files); err != nil {
+			return ret, err
+		}
+		ret.Files = append(ret.Files, figshareFiles(files)...)
+		if len(files) < 1000 {
+			break
+		}
+	}
+	return ret, nil
+}
+
+// figshareFiles convert Figshare files to dataset files, link-only files
+// are skipped
+func figshareFiles(files []types.FigshareFile) (ret []types.DatasetFile) {
+	for _, f := range files {
+		if f.IsLinkOnly {
+			continue
+		}
+		md5 := f.ComputedMd5
+		if md5 == "" {
+			md5 = f.SuppliedMd5
+		}
+		file := types.DatasetFile{Path: f.Name, URL: f.DownloadURL, Size: f.Size}
+		if md5 != "" {
+			file.Checksum = "md5:" + md5
+		}
+		ret = append(ret, file)
+	}
+	return ret
+}
+
+// DryadDataset list the files of Dryad dataset, version is the version
+// number or latest
+func DryadDataset(doi string, version string, bapiClis *types.BapiClisT) (*types.DatasetRecord, error) {
+	id := neturl.PathEscape("doi:" + doi)
+	dataset := types.DryadDataset{}
+	raw, err := getRawJSON("Dryad", fmt.Sprintf("%s/api/v2/datasets/%s", DryadAPIHost, id), bapiClis, &dataset)
+	if err != nil {
+		return nil, err
+	}
+	ret := &types.DatasetRecord{Repository: "dryad", ID: dataset.Identifier, Doi: doi,
+		Version: strconv.Itoa(dataset.VersionNumber), Title: dataset.Title, Record: raw}
+	versionLink := dataset.Links.Version.Href
+	if version != "" && version != "latest" {
+		versions := types.DryadVersionsRet{}
+		if err := getJSON("Dryad", fmt.Sprintf("%s/api/v2/datasets/%s/versions", DryadAPIHost, id), bapiClis, &versions); err != nil {
+			return ret, err
+		}
+		versionLink = ""
+		for _, v := range versions.Embedded.Versions {
+			if strconv.Itoa(v.VersionNumber) == version {
+				versionLink = v.Links.Self.Href
+				ret.Version = version
+			}
+		}
+		if versionLink == "" {
+			return ret, fmt.Errorf("version %s of %s not found", version, doi)
+		}
+	}
+	next := versionLink + "/files"
+	for next != "" {
+		files := types.DryadFilesRet{}
+		if err := getJSON("Dryad", DryadAPIHost+next, bapiClis, &files); err != nil {
+			return ret, err
+		}
+		for _, f := range files.Embedded.Files {
+			file := types.DatasetFile{Path: f.Path, URL: DryadAPIHost + f.Links.Download.Href, Size: f.Size}
+			if f.Digest != "" {
+				file.Checksum = strings.ToLower(strings.ReplaceAll(f.DigestType, "-", "")) + ":" + f.Digest
+			}
+			ret.Files = append(ret.Files, file)
+		}
+		next = files.Links.Next.Href
+	}
+	return ret, nil
+}
+
+// OsfDataset list the files of OSF project (osfstorage) recursively
+func OsfDataset(guid string, bapiClis *types.BapiClisT) (*types.DatasetRecord, error) {
+	guid = strings.ToLower(guid)
+	node := types.OsfNodeRet{}
+	raw, err := getRawJSON("OSF", fmt.Sprintf("%s/nodes/%s/", OsfAPIHost, guid), bapiClis, &node)
+	if err != nil {
+		return nil, err
+	}
+	ret := &types.DatasetRecord{Repository: "osf", ID: guid, Doi: "10.17605/OSF.IO/" + strings.ToUpper(guid),
+		Version: "latest", Title: node.Data.Attributes.Title, Record: raw}
+	folders := []string{fmt.Sprintf("%s/nodes/%s/files/osfstorage/", OsfAPIHost, guid)}
+	for len(folders) > 0 {
+		next := folders[0]
+		folders = folders[1:]
+		for next != "" {
+			files := types.OsfFilesRet{}
+			if err := getJSON("OSF", next, bapiClis, &files); err != nil {
+				return ret, err
+			}
+			for _, f := range files.Data {
+				attr := f.Attributes
+				if attr.Kind == "folder" {
+					folders = append(folders, f.Relationships.Files.Links.Related.Href)
+					continue
+				}
+				file := types.DatasetFile{Path: strings.TrimPrefix(attr.MaterializedPath, "/"), URL: f.Links.Download, Size: attr.Size}
+				if attr.Extra.Hashes.Sha256 != "" {
+					file.Checksum = "sha256:" + attr.Extra.Hashes.Sha256
+				} else if attr.Extra.Hashes.Md5 != "" {
+					file.Checksum = "md5:" + attr.Extra.Hashes.Md5
+				}
+				ret.Files = append(ret.Files, file)
+			}
+			next = files.Links.Next
+		}
+	}
+	return ret, nil
+}
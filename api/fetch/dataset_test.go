@@ -0,0 +1,41 @@
+package fetch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/openanno/bget/api/types"
+)
+
+func TestZenodoVersion(t *testing.T) {
+	data := `{"hits": {"total": 3, "hits": [
+  {"id": 3363061, "metadata": {"version": "v1.1", "relations": {"version": [{"index": 2}]}}},
+  {"id": 3363060, "metadata": {"version": "1.0", "relations": {"version": [{"index": 1}]}}},
+  {"id": 3363059, "metadata": {"relations": {"version": [{"index": 0}]}}}
+]}}`
+	ret := types.ZenodoVersionsRet{}
+	if err := json.Unmarshal([]byte(data), &ret); err != nil {
+		t.Fatal(err)
+	}
+	for version, want := range map[string]string{"v1.1": "3363061", "1.0": "3363060", "1": "3363059", "3": "3363061", "4": ""} {
+		if got := ZenodoVersion(ret.Hits.Hits, version); got != want {
+			t.Errorf("unexpected record of version %s: %s", version, got)
+		}
+	}
+}
+
+func TestFigshareFiles(t *testing.T) {
+	data := `{"id": 1, "version": 2, "files": [
+  {"name": "a.csv", "size": 10, "download_url": "https://ndownloader.figshare.com/files/1", "computed_md5": "abc"},
+  {"name": "b.txt", "size": 5, "download_url": "https://ndownloader.figshare.com/files/2", "supplied_md5": "def"},
+  {"name": "link", "is_link_only": true}
+]}`
+	article := types.FigshareArticle{}
+	if err := json.Unmarshal([]byte(data), &article); err != nil {
+		t.Fatal(err)
+	}
+	files := figshareFiles(article.Files)
+	if len(files) != 2 || files[0].Checksum != "md5:abc" || files[1].Checksum != "md5:def" {
+		t.Errorf("unexpected files: %+v", files)
+	}
+}
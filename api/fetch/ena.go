@@ -0,0 +1,64 @@
+package fetch
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	neturl "net/url"
+	"strings"
+
+	"github.com/openanno/bget/api/types"
+)
+
+// EnaPortalHost is the ENA portal API
+const EnaPortalHost = "https://www.ebi.ac.uk/ena/portal/api"
+
+// EnaRunFields is the default fields of ENA filereport
+var EnaRunFields = []string{"run_accession", "fastq_ftp", "fastq_md5", "fastq_bytes",
+	"submitted_ftp", "submitted_md5", "submitted_bytes"}
+
+// EnaFileReport query the read runs of accession (run, experiment, sample,
+// study or project) via https://www.ebi.ac.uk/ena/portal/api/filereport
+func EnaFileReport(accession string, fields []string, bapiClis *types.BapiClisT) ([]types.EnaRecord, error) {
+	params := neturl.Values{}
+	params.Set("accession", accession)
+	params.Set("result", "read_run")
+	params.Set("fields", strings.Join(fields, ","))
+	params.Set("format", "tsv")
+	buf, err := getBytes("ENA", fmt.Sprintf("%s/filereport?%s", EnaPortalHost, params.Encode()), bapiClis)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEnaReport(bytes.NewReader(buf))
+}
+
+// ParseEnaReport parse the TSV of ENA portal API
+func ParseEnaReport(r io.Reader) (records []types.EnaRecord, err error) {
+	reader := csv.NewReader(r)
+	reader.Comma = '\t'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return records, nil
+	} else if err != nil {
+		return records, err
+	}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return records, err
+		}
+		record := make(types.EnaRecord)
+		for i, v := range header {
+			if i < len(row) {
+				record[strings.TrimSpace(v)] = strings.TrimSpace(row[i])
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,47 @@
+package fetch
+
+import (
+	"bytes"
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"github.com/openanno/bget/api/types"
+)
+
+// EuropePmcHost is the Europe PMC RESTful API
+const EuropePmcHost = "https://www.ebi.ac.uk/europepmc/webservices/rest"
+
+// EuropePmcFullTextXML return the JATS XML of an open access PMC article
+func EuropePmcFullTextXML(pmcid string, bapiClis *types.BapiClisT) ([]byte, error) {
+	url := fmt.Sprintf("%s/%s/fullTextXML", EuropePmcHost, pmcid)
+	buf, err := getBytes("Europe PMC", url, bapiClis)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Contains(buf, []byte("<article")) {
+		return nil, fmt.Errorf("full text XML of %s is not available", pmcid)
+	}
+	return buf, nil
+}
+
+// NcbiEutilsHost is the NCBI E-utilities API
+const NcbiEutilsHost = "https://eutils.ncbi.nlm.nih.gov/entrez/eutils"
+
+// PmcFullTextXML return the JATS XML of an open access PMC article via
+// NCBI E-utilities efetch
+func PmcFullTextXML(pmcid string, bapiClis *types.BapiClisT) ([]byte, error) {
+	url := fmt.Sprintf("%s/efetch.fcgi?db=pmc&id=%s&tool=bget", NcbiEutilsHost,
+		strings.TrimPrefix(strings.ToUpper(pmcid), "PMC"))
+	if bapiClis.Email != "" {
+		url = url + "&email=" + neturl.QueryEscape(bapiClis.Email)
+	}
+	buf, err := getBytes("PMC", url, bapiClis)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Contains(buf, []byte("<body")) {
+		return nil, fmt.Errorf("full text XML of %s is not available in PMC", pmcid)
+	}
+	return buf, nil
+}
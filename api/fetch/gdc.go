@@ -12,6 +12,7 @@ import (
 	"code.cloudfoundry.org/bytefmt"
 	"github.com/olekukonko/tablewriter"
 	"github.com/openanno/bget/api/types"
+	"github.com/openanno/bget/cassette"
 	cio "github.com/openbiox/ligo/io"
 	cnet "github.com/openbiox/ligo/net"
 	mpb "github.com/vbauerster/mpb/v5"
@@ -32,7 +33,7 @@ var pg *mpb.Progress
 func Gdc(endpoint *types.GdcEndpoints, BapiClis *types.BapiClisT, of io.Writer) {
 	setLog(BapiClis)
 	netopt := setNetOpt(BapiClis)
-	client := cnet.NewHTTPClient(netopt.Timeout, netopt.Proxy)
+	client := cassette.NewHTTPClient(netopt.Timeout, netopt.Proxy)
 	host := GdcAPIHost
 	if endpoint.Legacy {
 		host = GdcAPIHostLegacy
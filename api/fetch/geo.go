@@ -0,0 +1,137 @@
+package fetch
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/openanno/bget/api/types"
+)
+
+// GeoHost is the GEO website
+const GeoHost = "https://www.ncbi.nlm.nih.gov/geo"
+
+// GeoFtpHost is the GEO FTP site over HTTPS
+const GeoFtpHost = "https://ftp.ncbi.nlm.nih.gov/geo"
+
+var geoMatrixRe = regexp.MustCompile(`href="([^"]+_series_matrix[.]txt[.]gz)"`)
+
+// GeoSeriesDir return the FTP dir of GEO series, e.g. series/GSE23nnn/GSE23543
+func GeoSeriesDir(gse string) string {
+	gse = strings.ToUpper(gse)
+	stub := "GSEnnn"
+	if len(gse) > 6 {
+		stub = gse[0:len(gse)-3] + "nnn"
+	}
+	return fmt.Sprintf("%s/series/%s/%s", GeoFtpHost, stub, gse)
+}
+
+// GeoSoft fetch the SOFT (brief, without data tables) of GEO series and
+// its samples and parse the samples
+func GeoSoft(acc string, bapiClis *types.BapiClisT) ([]types.GeoSample, error) {
+	url := fmt.Sprintf("%s/query/acc.cgi?acc=%s&targ=all&form=text&view=brief", GeoHost, acc)
+	buf, err := getBytes("GEO", url, bapiClis)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGeoSoft(bytes.NewReader(buf))
+}
+
+// GeoSeriesMatrixURLs list the series matrix files (one per platform) of GEO series
+func GeoSeriesMatrixURLs(gse string, bapiClis *types.BapiClisT) (urls []string, err error) {
+	dir := GeoSeriesDir(gse) + "/matrix/"
+	buf, err := getBytes("GEO", dir, bapiClis)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range geoMatrixRe.FindAllStringSubmatch(string(buf), -1) {
+		urls = append(urls, dir+m[1][strings.LastIndex(m[1], "/")+1:])
+	}
+	return urls, nil
+}
+
+// softLine split the line of SOFT into key and value, e.g. !Sample_title = liver
+func softLine(line string) (key string, value string) {
+	kv := strings.SplitN(line, "=", 2)
+	key = strings.TrimSpace(kv[0])
+	if len(kv) == 2 {
+		value = strings.TrimSpace(kv[1])
+	}
+	return key, value
+}
+
+// ParseGeoSoft parse the ^SAMPLE entities of GEO SOFT
+func ParseGeoSoft(r io.Reader) (samples []types.GeoSample, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	var sample *types.GeoSample
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "^") {
+			if sample != nil {
+				samples = append(samples, *sample)
+				sample = nil
+			}
+			if key, value := softLine(line); key == "^SAMPLE" {
+				sample = &types.GeoSample{Accession: value}
+			}
+			continue
+		}
+		if sample == nil || !strings.HasPrefix(line, "!Sample_") {
+			continue
+		}
+		key, value := softLine(line)
+		switch key {
+		case "!Sample_title":
+			sample.Title = value
+		case "!Sample_source_name_ch1":
+			sample.Source = value
+		case "!Sample_organism_ch1":
+			sample.Organism = value
+		case "!Sample_platform_id":
+			sample.Platform = value
+		case "!Sample_characteristics_ch1":
+			c := types.GeoCharacteristic{Key: "characteristics", Value: value}
+			if kv := strings.SplitN(value, ":", 2); len(kv) == 2 {
+				c = types.GeoCharacteristic{Key: strings.TrimSpace(kv[0]), Value: strings.TrimSpace(kv[1])}
+			}
+			sample.Characteristics = append(sample.Characteristics, c)
+		case "!Sample_relation":
+			sample.Relations = append(sample.Relations, value)
+		}
+	}
+	if sample != nil {
+		samples = append(samples, *sample)
+	}
+	return samples, scanner.Err()
+}
+
+// ParseGeoSeriesMatrix copy the data table of series matrix to w as TSV
+// without quotes, the number of rows (including the header) is returned
+func ParseGeoSeriesMatrix(r io.Reader, w io.Writer) (n int, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	inTable := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "!series_matrix_table_begin":
+			inTable = true
+		case line == "!series_matrix_table_end":
+			return n, nil
+		case inTable:
+			fields := strings.Split(line, "\t")
+			for i, v := range fields {
+				fields[i] = strings.Trim(v, `"`)
+			}
+			if _, err = fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, scanner.Err()
+}
@@ -0,0 +1,62 @@
+package fetch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseGeoSoft(t *testing.T) {
+	soft := `^SERIES = GSE23543
+!Series_title = Test series
+^SAMPLE = GSM577604
+!Sample_title = liver rep1
+!Sample_source_name_ch1 = liver
+!Sample_organism_ch1 = Homo sapiens
+!Sample_characteristics_ch1 = tissue: liver
+!Sample_characteristics_ch1 = age: 42 years
+!Sample_characteristics_ch1 = healthy donor
+!Sample_platform_id = GPL9052
+!Sample_relation = BioSample: https://www.ncbi.nlm.nih.gov/biosample/SAMN00012345
+!Sample_relation = SRA: https://www.ncbi.nlm.nih.gov/sra?term=SRX021003
+^SAMPLE = GSM577605
+!Sample_title = liver rep2
+^PLATFORM = GPL9052
+!Platform_title = Illumina
+`
+	samples, err := ParseGeoSoft(strings.NewReader(soft))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[1].Title != "liver rep2" {
+		t.Fatalf("unexpected samples: %+v", samples)
+	}
+	s := samples[0]
+	if s.Source != "liver" || s.Platform != "GPL9052" || len(s.Characteristics) != 3 ||
+		s.Characteristics[1].Key != "age" || s.Characteristics[1].Value != "42 years" ||
+		s.Characteristics[2].Key != "characteristics" {
+		t.Errorf("unexpected sample: %+v", s)
+	}
+	if srx := s.SraExperiments(); len(srx) != 1 || srx[0] != "SRX021003" {
+		t.Errorf("unexpected SRA experiments: %v", srx)
+	}
+	if dir := GeoSeriesDir("GSE23543"); dir != GeoFtpHost+"/series/GSE23nnn/GSE23543" {
+		t.Errorf("unexpected dir: %s", dir)
+	}
+	if dir := GeoSeriesDir("GSE123"); dir != GeoFtpHost+"/series/GSEnnn/GSE123" {
+		t.Errorf("unexpected dir: %s", dir)
+	}
+}
+
+func TestParseGeoSeriesMatrix(t *testing.T) {
+	matrix := "!Series_title\t\"Test\"\n!series_matrix_table_begin\n\"ID_REF\"\t\"GSM577604\"\t\"GSM577605\"\n" +
+		"\"1007_s_at\"\t5.1\t6.2\n!series_matrix_table_end\n"
+	var buf bytes.Buffer
+	n, err := ParseGeoSeriesMatrix(strings.NewReader(matrix), &buf)
+	if err != nil || n != 2 {
+		t.Fatalf("unexpected rows: %d %v", n, err)
+	}
+	if buf.String() != "ID_REF\tGSM577604\tGSM577605\n1007_s_at\t5.1\t6.2\n" {
+		t.Errorf("unexpected matrix: %q", buf.String())
+	}
+}
@@ -0,0 +1,37 @@
+package fetch
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"github.com/openanno/bget/api/types"
+)
+
+// NcbiIDConvHost is the NCBI PMC ID converter API
+const NcbiIDConvHost = "https://www.ncbi.nlm.nih.gov/pmc/utils/idconv/v1.0/"
+
+// NcbiIDConv convert PMID, PMCID and DOI via NCBI PMC ID converter API
+func NcbiIDConv(ids []string, bapiClis *types.BapiClisT) (records []types.NcbiIDConvRecord, err error) {
+	// at most 200 ids per request
+	for i := 0; i < len(ids); i += 200 {
+		end := i + 200
+		if end > len(ids) {
+			end = len(ids)
+		}
+		url := fmt.Sprintf("%s?tool=bget&format=json&ids=%s", NcbiIDConvHost,
+			neturl.QueryEscape(strings.Join(ids[i:end], ",")))
+		if bapiClis.Email != "" {
+			url = url + "&email=" + neturl.QueryEscape(bapiClis.Email)
+		}
+		ret := types.NcbiIDConvRet{}
+		if err = getJSON("NCBI ID converter", url, bapiClis, &ret); err != nil {
+			return records, err
+		}
+		if ret.Status != "ok" {
+			return records, fmt.Errorf("NCBI ID converter returns %s: %s", ret.Status, ret.Message)
+		}
+		records = append(records, ret.Records...)
+	}
+	return records, nil
+}
@@ -33,6 +33,10 @@ func Ncbi(bapiClis *types.BapiClisT, ncbiClis *types.NcbiClisT, of io.Writer) {
 		}
 		break
 	}
+	if err != nil {
+		log.Warnln(err)
+		return
+	}
 	log.Infof("Available retrieve %d records.", s.Count)
 	if s.Count == 0 {
 		return
@@ -0,0 +1,74 @@
+package fetch
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	neturl "net/url"
+
+	"github.com/openanno/bget/api/types"
+)
+
+// PubmedSearch search PubMed via Ncbi and return at most max structured records
+func PubmedSearch(query string, max int, bapiClis *types.BapiClisT) ([]types.PubmedArticle, error) {
+	clis := *bapiClis
+	clis.Query = query
+	clis.Format = "xml"
+	clis.XML2json = false
+	clis.From = -1
+	clis.Size = max
+	retmax := 500
+	if max > 0 && max < retmax {
+		retmax = max
+	}
+	var buf bytes.Buffer
+	Ncbi(&clis, &types.NcbiClisT{NcbiDB: "pubmed", NcbiRetmax: retmax}, &buf)
+	return ParsePubmedArticles(&buf)
+}
+
+// ParsePubmedArticles parse the PubmedArticle elements of (concatenated) PubmedArticleSet
+func ParsePubmedArticles(r io.Reader) (articles []types.PubmedArticle, err error) {
+	d := xml.NewDecoder(r)
+	d.Strict = false
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return articles, err
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "PubmedArticle" {
+			article := types.PubmedArticle{}
+			if err = d.DecodeElement(&article, &se); err != nil {
+				return articles, err
+			}
+			articles = append(articles, article)
+		}
+	}
+	return articles, nil
+}
+
+// EuropePmcSearch search Europe PMC and return at most max records
+func EuropePmcSearch(query string, max int, bapiClis *types.BapiClisT) (results []types.EuropePmcResult, err error) {
+	cursor := "*"
+	for {
+		params := neturl.Values{}
+		params.Set("query", query)
+		params.Set("format", "json")
+		params.Set("pageSize", "1000")
+		params.Set("cursorMark", cursor)
+		ret := types.EuropePmcSearchRet{}
+		if err = getJSON("Europe PMC", fmt.Sprintf("%s/search?%s", EuropePmcHost, params.Encode()), bapiClis, &ret); err != nil {
+			return results, err
+		}
+		results = append(results, ret.ResultList.Result...)
+		if max > 0 && len(results) >= max {
+			return results[0:max], nil
+		}
+		if len(ret.ResultList.Result) == 0 || ret.NextCursorMark == "" || ret.NextCursorMark == cursor {
+			return results, nil
+		}
+		cursor = ret.NextCursorMark
+	}
+}
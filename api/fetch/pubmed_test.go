@@ -0,0 +1,33 @@
+package fetch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePubmedArticles(t *testing.T) {
+	// efetch pages are concatenated PubmedArticleSet documents
+	input := `<?xml version="1.0" ?>
+<!DOCTYPE PubmedArticleSet PUBLIC "-//NLM//DTD PubMedArticle, 1st January 2019//EN" "https://dtd.nlm.nih.gov/ncbi/pubmed/out/pubmed_190101.dtd">
+<PubmedArticleSet><PubmedArticle><MedlineCitation><PMID Version="1">30487223</PMID>
+<Article><ArticleTitle>First article.</ArticleTitle></Article></MedlineCitation>
+<PubmedData><ArticleIdList><ArticleId IdType="pubmed">30487223</ArticleId><ArticleId IdType="pmc">PMC6287119</ArticleId>
+<ArticleId IdType="doi">10.1000/first</ArticleId></ArticleIdList></PubmedData></PubmedArticle></PubmedArticleSet>
+<?xml version="1.0" ?>
+<PubmedArticleSet><PubmedArticle><MedlineCitation><PMID Version="1">30402350</PMID>
+<Article><ArticleTitle>Second article.</ArticleTitle><ELocationID EIdType="doi" ValidYN="Y">10.1000/second</ELocationID></Article></MedlineCitation>
+<PubmedData><ArticleIdList><ArticleId IdType="pubmed">30402350</ArticleId></ArticleIdList></PubmedData></PubmedArticle></PubmedArticleSet>`
+	articles, err := ParsePubmedArticles(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 2 {
+		t.Fatalf("unexpected number of articles: %d", len(articles))
+	}
+	if articles[0].MedlineCitation.PMID != "30487223" || articles[0].ID("pmc") != "PMC6287119" || articles[0].ID("doi") != "10.1000/first" {
+		t.Errorf("unexpected article: %+v", articles[0])
+	}
+	if articles[1].ID("pmc") != "" || articles[1].ID("doi") != "10.1000/second" {
+		t.Errorf("unexpected article: %+v", articles[1])
+	}
+}
@@ -0,0 +1,106 @@
+package fetch
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	neturl "net/url"
+	"strconv"
+	"strings"
+
+	"github.com/openanno/bget/api/types"
+)
+
+// SraRunInfoPageSize is the number of runs fetched per efetch request
+const SraRunInfoPageSize = 500
+
+// SraRunInfo query the runinfo CSV of SRA runs matching term (run, study,
+// BioProject or GEO accessions) via NCBI E-utilities, the runs are fetched
+// in pages of SraRunInfoPageSize
+func SraRunInfo(term string, bapiClis *types.BapiClisT) ([]map[string]string, error) {
+	params := neturl.Values{}
+	params.Set("db", "sra")
+	params.Set("term", term)
+	params.Set("usehistory", "y")
+	params.Set("retmode", "json")
+	params.Set("tool", "bget")
+	if bapiClis.Email != "" {
+		params.Set("email", bapiClis.Email)
+	}
+	ret := types.NcbiESearchRet{}
+	if err := getJSON("NCBI SRA", fmt.Sprintf("%s/esearch.fcgi?%s", NcbiEutilsHost, params.Encode()), bapiClis, &ret); err != nil {
+		return nil, err
+	}
+	if ret.ESearchResult.Count == "0" || ret.ESearchResult.WebEnv == "" {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(ret.ESearchResult.Count)
+	if err != nil {
+		return nil, fmt.Errorf("invalid count of NCBI SRA esearch: %s", ret.ESearchResult.Count)
+	}
+	return sraRunInfoPages(count, SraRunInfoPageSize, func(retstart, retmax int) ([]byte, error) {
+		params := neturl.Values{}
+		params.Set("db", "sra")
+		params.Set("query_key", ret.ESearchResult.QueryKey)
+		params.Set("WebEnv", ret.ESearchResult.WebEnv)
+		params.Set("rettype", "runinfo")
+		params.Set("retmode", "csv")
+		params.Set("retstart", strconv.Itoa(retstart))
+		params.Set("retmax", strconv.Itoa(retmax))
+		params.Set("tool", "bget")
+		if bapiClis.Email != "" {
+			params.Set("email", bapiClis.Email)
+		}
+		return getBytes("NCBI SRA", fmt.Sprintf("%s/efetch.fcgi?%s", NcbiEutilsHost, params.Encode()), bapiClis)
+	})
+}
+
+// sraRunInfoPages fetch the count records in pages of pageSize and parse
+// the concatenated runinfo CSV
+func sraRunInfoPages(count int, pageSize int, fetchPage func(retstart, retmax int) ([]byte, error)) ([]map[string]string, error) {
+	var buf bytes.Buffer
+	for retstart := 0; retstart < count; retstart += pageSize {
+		page, err := fetchPage(retstart, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(page)
+		if len(page) > 0 && page[len(page)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+	return ParseSraRunInfo(&buf)
+}
+
+// ParseSraRunInfo parse the runinfo CSV of SRA, the repeated headers of
+// concatenated pages are skipped
+func ParseSraRunInfo(r io.Reader) (records []map[string]string, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return records, nil
+	} else if err != nil {
+		return records, err
+	}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return records, err
+		}
+		if len(row) == 0 || row[0] == header[0] || strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		record := make(map[string]string)
+		for i, v := range header {
+			if i < len(row) {
+				record[v] = strings.TrimSpace(row[i])
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,29 @@
+package fetch
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSraRunInfoPages(t *testing.T) {
+	var requests []string
+	fetchPage := func(retstart, retmax int) ([]byte, error) {
+		requests = append(requests, fmt.Sprintf("%d:%d", retstart, retmax))
+		page := "Run,spots,LibraryLayout\n"
+		for i := retstart; i < retstart+retmax && i < 5; i++ {
+			page += fmt.Sprintf("SRR%07d,%d,PAIRED\n", i, i*100)
+		}
+		// the last page has no trailing newline
+		return []byte(page[0 : len(page)-1]), nil
+	}
+	records, err := sraRunInfoPages(5, 2, fetchPage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 3 || requests[2] != "4:2" {
+		t.Errorf("unexpected requests: %v", requests)
+	}
+	if len(records) != 5 || records[0]["Run"] != "SRR0000000" || records[4]["Run"] != "SRR0000004" || records[4]["spots"] != "400" {
+		t.Errorf("unexpected records: %v", records)
+	}
+}
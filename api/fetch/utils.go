@@ -14,6 +14,7 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	xj "github.com/basgys/goxml2json"
 	"github.com/openanno/bget/api/types"
+	"github.com/openanno/bget/cassette"
 	cio "github.com/openbiox/ligo/io"
 	clog "github.com/openbiox/ligo/log"
 	cnet "github.com/openbiox/ligo/net"
@@ -43,7 +44,7 @@ func setNetOpt(bapiClis *types.BapiClisT) *cnet.Params {
 
 func PostReq(siteName, url string, data []byte, bapiClis *types.BapiClisT, netopt *cnet.Params, of io.Writer) {
 	method := "POST"
-	client := cnet.NewHTTPClient(bapiClis.Timeout, bapiClis.Proxy)
+	client := cassette.NewHTTPClient(bapiClis.Timeout, bapiClis.Proxy)
 	req, err := http.NewRequest(method, url, bytes.NewBuffer(data))
 	cnet.SetDefaultReqHeader(req)
 	if err != nil {
@@ -56,7 +57,7 @@ func PostReq(siteName, url string, data []byte, bapiClis *types.BapiClisT, netop
 
 func GetReq(siteName, url string, bapiClis *types.BapiClisT, netopt *cnet.Params, of io.Writer) {
 	method := "GET"
-	client := cnet.NewHTTPClient(bapiClis.Timeout, bapiClis.Proxy)
+	client := cassette.NewHTTPClient(bapiClis.Timeout, bapiClis.Proxy)
 	req, err := http.NewRequest(method, url, nil)
 	cnet.SetDefaultReqHeader(req)
 	if err != nil {
@@ -68,7 +69,7 @@ func GetReq(siteName, url string, bapiClis *types.BapiClisT, netopt *cnet.Params
 
 func GetReq2(url string, bapiClis *types.BapiClisT, netopt *cnet.Params, of io.Writer) {
 	method := "GET"
-	client := cnet.NewHTTPClient(bapiClis.Timeout, bapiClis.Proxy)
+	client := cassette.NewHTTPClient(bapiClis.Timeout, bapiClis.Proxy)
 	req, err := http.NewRequest(method, url, nil)
 	cnet.SetDefaultReqHeader(req)
 	if err != nil {
@@ -222,3 +223,31 @@ func xml2json(buf *bytes.Buffer, PrettyJSON bool, indentNum int, sortKey bool) {
 		*buf = *bytes.NewBuffer(json.Bytes())
 	}
 }
+
+func getBytes(siteName, url string, bapiClis *types.BapiClisT) ([]byte, error) {
+	netopt := setNetOpt(bapiClis)
+	client := cassette.NewHTTPClient(bapiClis.Timeout, bapiClis.Proxy)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	cnet.SetDefaultReqHeader(req)
+	log.Infof("Quering %s API: %s.", siteName, url)
+	resp, err := cnet.RetriesClient(client, req, netopt)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("empty response: %s", url)
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+func getJSON(siteName, url string, bapiClis *types.BapiClisT, v interface{}) error {
+	buf, err := getBytes(siteName, url, bapiClis)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, v)
+}
@@ -0,0 +1,28 @@
+package types
+
+// BiorxivDetailsRet is the returned data of https://api.biorxiv.org/details
+type BiorxivDetailsRet struct {
+	Messages   []BiorxivMessage `json:"messages"`
+	Collection []BiorxivVersion `json:"collection"`
+}
+
+// BiorxivMessage is the status message of bioRxiv/medRxiv API
+type BiorxivMessage struct {
+	Status string `json:"status"`
+}
+
+// BiorxivVersion is one version of a bioRxiv/medRxiv preprint
+type BiorxivVersion struct {
+	Doi                 string `json:"doi"`
+	Title               string `json:"title"`
+	Authors             string `json:"authors"`
+	AuthorCorresponding string `json:"author_corresponding"`
+	Date                string `json:"date"`
+	Version             string `json:"version"`
+	Type                string `json:"type"`
+	License             string `json:"license"`
+	Category            string `json:"category"`
+	Jatsxml             string `json:"jatsxml"`
+	Published           string `json:"published"`
+	Server              string `json:"server"`
+}
@@ -0,0 +1,25 @@
+package types
+
+// OpenCitationsCitation is the returned item of https://opencitations.net/index/coci/api/v1/citations
+type OpenCitationsCitation struct {
+	Oci      string `json:"oci"`
+	Citing   string `json:"citing"`
+	Cited    string `json:"cited"`
+	Creation string `json:"creation"`
+}
+
+// OpenAlexWorksRet is the returned data of https://api.openalex.org/works
+type OpenAlexWorksRet struct {
+	Meta struct {
+		Count      int    `json:"count"`
+		NextCursor string `json:"next_cursor"`
+	} `json:"meta"`
+	Results []OpenAlexWork `json:"results"`
+}
+
+// OpenAlexWork is the work item of OpenAlex
+type OpenAlexWork struct {
+	ID    string `json:"id"`
+	Doi   string `json:"doi"`
+	Title string `json:"title"`
+}
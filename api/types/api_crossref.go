@@ -1,5 +1,10 @@
 package types
 
+import (
+	"fmt"
+	"strings"
+)
+
 type CrossRefEndpoints struct {
 	Doi          CrossRefDoiPost
 	ArticleTitle CrossRefTitlePost
@@ -39,3 +44,119 @@ type CrossRefDoiPost struct {
 	ResType   string `json:"resType"`
 	DoiSearch string `json:"doi_search"`
 }
+
+// CrossRefWorkRet is the response of https://api.crossref.org/works/{doi}
+type CrossRefWorkRet struct {
+	Status  string       `json:"status"`
+	Message CrossRefWork `json:"message"`
+}
+
+// CrossRefWork is the message of CrossRef REST API works endpoint
+type CrossRefWork struct {
+	DOI                 string                        `json:"DOI"`
+	URL                 string                        `json:"URL"`
+	Type                string                        `json:"type"`
+	Publisher           string                        `json:"publisher"`
+	Title               []string                      `json:"title"`
+	ShortTitle          []string                      `json:"short-title"`
+	ContainerTitle      []string                      `json:"container-title"`
+	ShortContainerTitle []string                      `json:"short-container-title"`
+	ISSN                []string                      `json:"ISSN"`
+	Volume              string                        `json:"volume"`
+	Issue               string                        `json:"issue"`
+	Page                string                        `json:"page"`
+	Author              []CrossRefAuthor              `json:"author"`
+	Issued              CrossRefDateParts             `json:"issued"`
+	PublishedPrint      CrossRefDateParts             `json:"published-print"`
+	PublishedOnline     CrossRefDateParts             `json:"published-online"`
+	ReferenceCount      int                           `json:"reference-count"`
+	IsReferencedByCount int                           `json:"is-referenced-by-count"`
+	Score               float64                       `json:"score"`
+	Reference           []CrossRefReference           `json:"reference"`
+	UpdateTo            []CrossRefUpdate              `json:"update-to"`
+	UpdatedBy           []CrossRefUpdate              `json:"updated-by"`
+	Relation            map[string][]CrossRefRelation `json:"relation"`
+	License             []CrossRefLicense             `json:"license"`
+}
+
+// CrossRefLicense is the license item of CrossRefWork, ContentVersion is
+// vor (version of record), am (accepted manuscript), tdm or unspecified
+type CrossRefLicense struct {
+	URL            string            `json:"URL"`
+	ContentVersion string            `json:"content-version"`
+	DelayInDays    int               `json:"delay-in-days"`
+	Start          CrossRefDateParts `json:"start"`
+}
+
+// CrossRefUpdate is the update-to (or updated-by) item of CrossRefWork,
+// Type is retraction, correction, expression_of_concern, etc.
+type CrossRefUpdate struct {
+	DOI     string            `json:"DOI"`
+	Type    string            `json:"type"`
+	Label   string            `json:"label"`
+	Updated CrossRefDateParts `json:"updated"`
+}
+
+// CrossRefRelation is the relation item of CrossRefWork
+type CrossRefRelation struct {
+	IDType     string `json:"id-type"`
+	ID         string `json:"id"`
+	AssertedBy string `json:"asserted-by"`
+}
+
+// CrossRefReference is the reference item of CrossRefWork
+type CrossRefReference struct {
+	Key          string `json:"key"`
+	DOI          string `json:"DOI"`
+	Unstructured string `json:"unstructured"`
+	ArticleTitle string `json:"article-title"`
+	Year         string `json:"year"`
+}
+
+// CrossRefWorksRet is the response of https://api.crossref.org/works
+type CrossRefWorksRet struct {
+	Status  string `json:"status"`
+	Message struct {
+		TotalResults int            `json:"total-results"`
+		NextCursor   string         `json:"next-cursor"`
+		Items        []CrossRefWork `json:"items"`
+	} `json:"message"`
+}
+
+// CrossRefAuthor is the author item of CrossRefWork
+type CrossRefAuthor struct {
+	Given    string `json:"given"`
+	Family   string `json:"family"`
+	Name     string `json:"name"`
+	Sequence string `json:"sequence"`
+	ORCID    string `json:"ORCID"`
+}
+
+// CrossRefDateParts is the date item of CrossRefWork
+type CrossRefDateParts struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// Year return the first year of date-parts
+func (d CrossRefDateParts) Year() int {
+	if len(d.DateParts) == 0 || len(d.DateParts[0]) == 0 {
+		return 0
+	}
+	return d.DateParts[0][0]
+}
+
+// Date return the first date of date-parts, e.g. 2019-01-02
+func (d CrossRefDateParts) Date() string {
+	if len(d.DateParts) == 0 || len(d.DateParts[0]) == 0 || d.DateParts[0][0] == 0 {
+		return ""
+	}
+	parts := []string{}
+	for i, v := range d.DateParts[0] {
+		if i == 0 {
+			parts = append(parts, fmt.Sprintf("%04d", v))
+		} else {
+			parts = append(parts, fmt.Sprintf("%02d", v))
+		}
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,178 @@
+package types
+
+import "encoding/json"
+
+// ZenodoRecord is the response of https://zenodo.org/api/records/{id}
+type ZenodoRecord struct {
+	ID           json.Number `json:"id"`
+	ConceptRecID string      `json:"conceptrecid"`
+	Doi          string      `json:"doi"`
+	Metadata     struct {
+		Title     string `json:"title"`
+		Version   string `json:"version"`
+		Relations struct {
+			Version []struct {
+				Index int `json:"index"`
+			} `json:"version"`
+		} `json:"relations"`
+	} `json:"metadata"`
+	Files []ZenodoFile `json:"files"`
+}
+
+// ZenodoVersionsRet is the response of https://zenodo.org/api/records/{id}/versions
+type ZenodoVersionsRet struct {
+	Hits struct {
+		Hits  []ZenodoRecord `json:"hits"`
+		Total int            `json:"total"`
+	} `json:"hits"`
+}
+
+// ZenodoFilesRet is the response of https://zenodo.org/api/records/{id}/files
+type ZenodoFilesRet struct {
+	Entries []ZenodoFile `json:"entries"`
+}
+
+// ZenodoFile is the file of ZenodoRecord, the legacy API uses filename and filesize
+type ZenodoFile struct {
+	Key      string `json:"key"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+	Filesize int64  `json:"filesize"`
+	Checksum string `json:"checksum"`
+	Links    struct {
+		Self     string `json:"self"`
+		Content  string `json:"content"`
+		Download string `json:"download"`
+	} `json:"links"`
+}
+
+// FigshareArticle is the response of https://api.figshare.com/v2/articles/{id}
+type FigshareArticle struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Doi     string `json:"doi"`
+	Version int    `json:"version"`
+	// Files is only the full file list of versioned articles
+	// (/articles/{id}/versions/{n}), use /articles/{id}/files for latest
+	Files []FigshareFile `json:"files"`
+}
+
+// FigshareFile is the file of https://api.figshare.com/v2/articles/{id}/files
+type FigshareFile struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	DownloadURL string `json:"download_url"`
+	ComputedMd5 string `json:"computed_md5"`
+	SuppliedMd5 string `json:"supplied_md5"`
+	IsLinkOnly  bool   `json:"is_link_only"`
+}
+
+// DryadDataset is the response of https://datadryad.org/api/v2/datasets/{doi}
+type DryadDataset struct {
+	Identifier    string `json:"identifier"`
+	Title         string `json:"title"`
+	VersionNumber int    `json:"versionNumber"`
+	Links         struct {
+		Version DryadLink `json:"stash:version"`
+	} `json:"_links"`
+}
+
+// DryadVersionsRet is the response of https://datadryad.org/api/v2/datasets/{doi}/versions
+type DryadVersionsRet struct {
+	Embedded struct {
+		Versions []struct {
+			VersionNumber int `json:"versionNumber"`
+			Links         struct {
+				Self DryadLink `json:"self"`
+			} `json:"_links"`
+		} `json:"stash:versions"`
+	} `json:"_embedded"`
+}
+
+// DryadFilesRet is the response of https://datadryad.org/api/v2/versions/{id}/files
+type DryadFilesRet struct {
+	Embedded struct {
+		Files []struct {
+			Path       string `json:"path"`
+			Size       int64  `json:"size"`
+			Digest     string `json:"digest"`
+			DigestType string `json:"digestType"`
+			Links      struct {
+				Download DryadLink `json:"stash:download"`
+			} `json:"_links"`
+		} `json:"stash:files"`
+	} `json:"_embedded"`
+	Links struct {
+		Next DryadLink `json:"next"`
+	} `json:"_links"`
+}
+
+// DryadLink is the HAL link of Dryad API
+type DryadLink struct {
+	Href string `json:"href"`
+}
+
+// OsfNodeRet is the response of https://api.osf.io/v2/nodes/{guid}/
+type OsfNodeRet struct {
+	Data struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Title string `json:"title"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// OsfFilesRet is the response of https://api.osf.io/v2/nodes/{guid}/files/osfstorage/
+type OsfFilesRet struct {
+	Data []struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Kind             string `json:"kind"`
+			Name             string `json:"name"`
+			MaterializedPath string `json:"materialized_path"`
+			Size             int64  `json:"size"`
+			Extra            struct {
+				Hashes struct {
+					Md5    string `json:"md5"`
+					Sha256 string `json:"sha256"`
+				} `json:"hashes"`
+			} `json:"extra"`
+		} `json:"attributes"`
+		Links struct {
+			Download string `json:"download"`
+		} `json:"links"`
+		Relationships struct {
+			Files struct {
+				Links struct {
+					Related struct {
+						Href string `json:"href"`
+					} `json:"related"`
+				} `json:"links"`
+			} `json:"files"`
+		} `json:"relationships"`
+	} `json:"data"`
+	Links struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+// DatasetRecord is a record of dataset repository with all its files
+type DatasetRecord struct {
+	Repository string          `json:"repository"`
+	ID         string          `json:"id"`
+	Doi        string          `json:"doi"`
+	Version    string          `json:"version"`
+	Title      string          `json:"title"`
+	Files      []DatasetFile   `json:"files"`
+	Record     json.RawMessage `json:"record,omitempty"`
+}
+
+// DatasetFile is a file of DatasetRecord, Checksum is algorithm:value, e.g. md5:abc
+type DatasetFile struct {
+	Path     string `json:"path"`
+	URL      string `json:"url"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum,omitempty"`
+	Verified *bool  `json:"verified,omitempty"`
+}
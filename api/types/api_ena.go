@@ -0,0 +1,16 @@
+package types
+
+import "strings"
+
+// EnaRecord is a row of ENA portal filereport (or search) TSV by field name
+type EnaRecord map[string]string
+
+// List split the ;-separated values of field, e.g. fastq_ftp of paired runs
+func (r EnaRecord) List(field string) (values []string) {
+	for _, v := range strings.Split(r[field], ";") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
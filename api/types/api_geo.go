@@ -0,0 +1,40 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+)
+
+var geoSraTermRe = regexp.MustCompile(`term=([SED]RX[0-9]+)`)
+
+// GeoSample is a ^SAMPLE entity of GEO SOFT
+type GeoSample struct {
+	Accession string
+	Title     string
+	Source    string
+	Organism  string
+	Platform  string
+	// Characteristics is the key: value of !Sample_characteristics_ch1 in order
+	Characteristics []GeoCharacteristic
+	// Relations is the !Sample_relation, e.g. SRA: https://www.ncbi.nlm.nih.gov/sra?term=SRX021003
+	Relations []string
+}
+
+// GeoCharacteristic is a characteristic of GEO sample
+type GeoCharacteristic struct {
+	Key   string
+	Value string
+}
+
+// SraExperiments return the SRA experiments in the relations of sample
+func (s *GeoSample) SraExperiments() (srx []string) {
+	for _, v := range s.Relations {
+		if !strings.HasPrefix(v, "SRA:") {
+			continue
+		}
+		if m := geoSraTermRe.FindStringSubmatch(v); m != nil {
+			srx = append(srx, m[1])
+		}
+	}
+	return srx
+}
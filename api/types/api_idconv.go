@@ -0,0 +1,68 @@
+package types
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+// FlexString accept both JSON string and number
+type FlexString string
+
+// UnmarshalJSON implement json.Unmarshaler
+func (s *FlexString) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		*s = FlexString(str)
+		return nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(b, &num); err != nil {
+		return err
+	}
+	*s = FlexString(num.String())
+	return nil
+}
+
+// NcbiIDConvRet is the response of https://www.ncbi.nlm.nih.gov/pmc/utils/idconv/v1.0/
+type NcbiIDConvRet struct {
+	Status       string             `json:"status"`
+	ResponseDate string             `json:"responseDate"`
+	Request      string             `json:"request"`
+	Message      string             `json:"message"`
+	Records      []NcbiIDConvRecord `json:"records"`
+}
+
+// NcbiIDConvRecord is the record of NcbiIDConvRet
+type NcbiIDConvRecord struct {
+	Pmcid       FlexString `json:"pmcid"`
+	Pmid        FlexString `json:"pmid"`
+	Doi         FlexString `json:"doi"`
+	RequestedID FlexString `json:"requested-id"`
+	Status      string     `json:"status"`
+	Errmsg      string     `json:"errmsg"`
+}
+
+// ArxivFeed is the Atom feed of http://export.arxiv.org/api/query
+type ArxivFeed struct {
+	XMLName xml.Name     `xml:"feed"`
+	Entries []ArxivEntry `xml:"entry"`
+}
+
+// ArxivEntry is the entry of ArxivFeed
+type ArxivEntry struct {
+	ID         string      `xml:"id"`
+	Title      string      `xml:"title"`
+	Published  string      `xml:"published"`
+	Updated    string      `xml:"updated"`
+	Doi        string      `xml:"http://arxiv.org/schemas/atom doi"`
+	JournalRef string      `xml:"http://arxiv.org/schemas/atom journal_ref"`
+	Links      []ArxivLink `xml:"link"`
+}
+
+// ArxivLink is the link of ArxivEntry
+type ArxivLink struct {
+	Href  string `xml:"href,attr"`
+	Rel   string `xml:"rel,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
@@ -0,0 +1,68 @@
+package types
+
+// PubmedArticle is the PubmedArticle element of efetch (db=pubmed, rettype=xml)
+type PubmedArticle struct {
+	MedlineCitation struct {
+		PMID    string `xml:"PMID"`
+		Article struct {
+			ArticleTitle string `xml:"ArticleTitle"`
+			Journal      struct {
+				Title string `xml:"Title"`
+			} `xml:"Journal"`
+			ELocationID []PubmedID `xml:"ELocationID"`
+		} `xml:"Article"`
+	} `xml:"MedlineCitation"`
+	PubmedData struct {
+		ArticleIDList []PubmedID `xml:"ArticleIdList>ArticleId"`
+	} `xml:"PubmedData"`
+}
+
+// PubmedID is the ArticleId or ELocationID of PubmedArticle
+type PubmedID struct {
+	IDType  string `xml:"IdType,attr"`
+	EIDType string `xml:"EIdType,attr"`
+	Value   string `xml:",chardata"`
+}
+
+// ID return the article ID of type, e.g. doi or pmc
+func (a *PubmedArticle) ID(idType string) string {
+	for _, v := range a.PubmedData.ArticleIDList {
+		if v.IDType == idType {
+			return v.Value
+		}
+	}
+	for _, v := range a.MedlineCitation.Article.ELocationID {
+		if v.EIDType == idType {
+			return v.Value
+		}
+	}
+	return ""
+}
+
+// EuropePmcSearchRet is the response of https://www.ebi.ac.uk/europepmc/webservices/rest/search
+type EuropePmcSearchRet struct {
+	HitCount       int    `json:"hitCount"`
+	NextCursorMark string `json:"nextCursorMark"`
+	ResultList     struct {
+		Result []EuropePmcResult `json:"result"`
+	} `json:"resultList"`
+}
+
+// EuropePmcResult is the result of EuropePmcSearchRet
+type EuropePmcResult struct {
+	ID    string `json:"id"`
+	Pmid  string `json:"pmid"`
+	Pmcid string `json:"pmcid"`
+	Doi   string `json:"doi"`
+	Title string `json:"title"`
+}
+
+// NcbiESearchRet is the JSON response of NCBI E-utilities esearch
+type NcbiESearchRet struct {
+	ESearchResult struct {
+		Count    string   `json:"count"`
+		QueryKey string   `json:"querykey"`
+		WebEnv   string   `json:"webenv"`
+		IDList   []string `json:"idlist"`
+	} `json:"esearchresult"`
+}
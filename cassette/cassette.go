@@ -0,0 +1,260 @@
+// Package cassette records HTTP request/response pairs on disk and replays
+// them, so that spiders and API clients can be tested without network.
+//
+// It is enabled by the BGET_CASSETTE environment variable (record or replay).
+// The cassette file is $BGET_CASSETTE_DIR/$BGET_CASSETTE_NAME.json
+// (default testdata/cassettes/bget.json).
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+	"unicode/utf8"
+
+	glog "github.com/openbiox/ligo/log"
+	cnet "github.com/openbiox/ligo/net"
+)
+
+var log = glog.Logger
+
+const (
+	// ModeRecord sends the requests and saves the responses
+	ModeRecord = "record"
+	// ModeReplay serves the saved responses without network
+	ModeReplay = "replay"
+)
+
+// ErrNotRecorded is returned in replay mode if no response is recorded for a request
+var ErrNotRecorded = errors.New("cassette: request not recorded")
+
+// Cassette is a list of recorded HTTP interactions
+type Cassette struct {
+	Path         string         `json:"-"`
+	Mode         string         `json:"-"`
+	Interactions []*Interaction `json:"interactions"`
+	used         map[int]bool
+	lock         sync.Mutex
+}
+
+// Interaction is one recorded request/response pair
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded HTTP request
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is the recorded HTTP response, binary body is saved in BodyBase64
+type Response struct {
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+var active *Cassette
+var activeLock sync.Mutex
+var defaultTransport http.RoundTripper
+
+func init() {
+	if mode := os.Getenv("BGET_CASSETTE"); mode != "" {
+		if err := Start(mode, Path(os.Getenv("BGET_CASSETTE_NAME"))); err != nil {
+			log.Warnln(err)
+		}
+	}
+}
+
+// Path return the cassette file of name under $BGET_CASSETTE_DIR
+func Path(name string) string {
+	dir := os.Getenv("BGET_CASSETTE_DIR")
+	if dir == "" {
+		dir = path.Join("testdata", "cassettes")
+	}
+	if name == "" {
+		name = "bget"
+	}
+	return path.Join(dir, name+".json")
+}
+
+// Start activate the cassette fn in record or replay mode, and install it
+// to http.DefaultTransport
+func Start(mode string, fn string) error {
+	if mode != ModeRecord && mode != ModeReplay {
+		return fmt.Errorf("cassette: unsupported mode %s (record, replay)", mode)
+	}
+	c := &Cassette{Path: fn, Mode: mode, used: make(map[int]bool)}
+	if mode == ModeReplay {
+		buf, err := ioutil.ReadFile(fn)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(buf, c); err != nil {
+			return fmt.Errorf("cassette: %s: %v", fn, err)
+		}
+	}
+	activeLock.Lock()
+	defer activeLock.Unlock()
+	active = c
+	if defaultTransport == nil {
+		defaultTransport = http.DefaultTransport
+		http.DefaultTransport = &Transport{Base: defaultTransport}
+	}
+	return nil
+}
+
+// Stop deactivate the cassette and restore http.DefaultTransport
+func Stop() {
+	activeLock.Lock()
+	defer activeLock.Unlock()
+	active = nil
+	if defaultTransport != nil {
+		http.DefaultTransport = defaultTransport
+		defaultTransport = nil
+	}
+}
+
+// Enabled indicates whether a cassette is active
+func Enabled() bool {
+	return current() != nil
+}
+
+func current() *Cassette {
+	activeLock.Lock()
+	defer activeLock.Unlock()
+	return active
+}
+
+// NewHTTPClient is cnet.NewHTTPClient using the active cassette
+func NewHTTPClient(timeout int, proxy string) *http.Client {
+	client := cnet.NewHTTPClient(timeout, proxy)
+	if Enabled() {
+		client.Transport = &Transport{Base: client.Transport}
+	}
+	return client
+}
+
+// Transport records or replays the requests via the active cassette,
+// requests are sent by Base if no cassette is active
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := current()
+	if c == nil {
+		return t.Base.RoundTrip(req)
+	}
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+	key := Request{Method: req.Method, URL: req.URL.String(), Body: string(reqBody)}
+	if c.Mode == ModeReplay {
+		it := c.match(key)
+		if it == nil {
+			return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, key.Method, key.URL)
+		}
+		return it.Response.httpResponse(req)
+	}
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	it := &Interaction{Request: key, Response: Response{Status: resp.StatusCode, Header: resp.Header}}
+	if utf8.Valid(body) {
+		it.Response.Body = string(body)
+	} else {
+		it.Response.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+	if err = c.add(it); err != nil {
+		log.Warnln(err)
+	}
+	return resp, nil
+}
+
+// match return the first unused interaction of the request, or the last
+// used one for revisits
+func (c *Cassette) match(key Request) *Interaction {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	last := -1
+	for i, v := range c.Interactions {
+		if v.Request != key {
+			continue
+		}
+		if !c.used[i] {
+			c.used[i] = true
+			return v
+		}
+		last = i
+	}
+	if last >= 0 {
+		return c.Interactions[last]
+	}
+	return nil
+}
+
+// add append the interaction and save the cassette
+func (c *Cassette) add(it *Interaction) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Interactions = append(c.Interactions, it)
+	buf, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(path.Dir(c.Path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.Path, buf, 0664)
+}
+
+func (r *Response) httpResponse(req *http.Request) (*http.Response, error) {
+	body := []byte(r.Body)
+	if r.BodyBase64 != "" {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(r.BodyBase64); err != nil {
+			return nil, err
+		}
+	}
+	header := r.Header
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		StatusCode:    r.Status,
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package cassette
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := path.Join(dir, "test.json")
+	n := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		fmt.Fprintf(w, "hello %s %d", r.URL.Path, n)
+	}))
+	get := func(url string) (string, error) {
+		resp, err := NewHTTPClient(5, "").Get(url)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		buf, err := ioutil.ReadAll(resp.Body)
+		return string(buf), err
+	}
+	if err := Start(ModeRecord, fn); err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := get(ts.URL + "/a")
+	Stop()
+	ts.Close()
+	if err != nil || recorded != "hello /a 1" {
+		t.Fatalf("unexpected record: %s %v", recorded, err)
+	}
+
+	if err := Start(ModeReplay, fn); err != nil {
+		t.Fatal(err)
+	}
+	defer Stop()
+	for i := 0; i < 2; i++ {
+		if replayed, err := get(ts.URL + "/a"); err != nil || replayed != recorded {
+			t.Errorf("unexpected replay: %s %v", replayed, err)
+		}
+	}
+	if _, err := get(ts.URL + "/b"); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("expected ErrNotRecorded, got %v", err)
+	}
+}
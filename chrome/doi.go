@@ -2,7 +2,6 @@ package chrome
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
@@ -10,26 +9,58 @@ import (
 	glog "github.com/openbiox/ligo/log"
 	stringo "github.com/openbiox/ligo/stringo"
 
-	"github.com/chromedp/chromedp"
 	cdp "github.com/chromedp/chromedp"
 )
 
 var log = glog.Logger
 
-// DoiSupplURLs query supplementary files from url
-func DoiSupplURLs(url string, timeout time.Duration, proxy string) []string {
-	// create context
-	o := append(cdp.DefaultExecAllocatorOptions[:]) //... any options here
+var execPath string
+var wsURL string
+var enabled bool
+
+// SetBrowser enable the chromedp tasks, execPath is the local Chrome binary
+// and wsURL is the websocket debugger URL of a running instance (preferred)
+func SetBrowser(path string, ws string) {
+	execPath, wsURL, enabled = path, ws, true
+}
+
+// Enabled indicates whether the browser is enabled by SetBrowser
+func Enabled() bool {
+	return enabled
+}
 
-	cx, cancel := cdp.NewExecAllocator(context.Background(), o...)
-	ctx, cancel := cdp.NewContext(cx)
-	ctx, cancel = context.WithTimeout(ctx, timeout)
+// newContext return a chromedp context with timeout using the local or
+// remote browser
+func newContext(timeout time.Duration, proxy string) (context.Context, context.CancelFunc) {
+	var allocCtx context.Context
+	var allocCancel context.CancelFunc
+	if wsURL != "" {
+		allocCtx, allocCancel = cdp.NewRemoteAllocator(context.Background(), wsURL)
+	} else {
+		o := make([]cdp.ExecAllocatorOption, len(cdp.DefaultExecAllocatorOptions))
+		copy(o, cdp.DefaultExecAllocatorOptions[:])
+		if execPath != "" {
+			o = append(o, cdp.ExecPath(execPath))
+		}
+		if proxy != "" {
+			o = append(o, cdp.ProxyServer(proxy))
+		}
+		allocCtx, allocCancel = cdp.NewExecAllocator(context.Background(), o...)
+	}
+	ctx, ctxCancel := cdp.NewContext(allocCtx)
+	ctx, timeoutCancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		timeoutCancel()
+		ctxCancel()
+		allocCancel()
+	}
+}
+
+// DoiSupplURLs query supplementary files from url
+func DoiSupplURLs(url string, timeout time.Duration, proxy string) (urls []string, err error) {
+	ctx, cancel := newContext(timeout, proxy)
 	defer cancel()
-	var err error
 	var attbs []map[string]string
-	urls := []string{}
-	// run task list
-	//err := cdp.Run(ctx, visibleNejm("https://www.nejm.org/doi/full/10.1056/NEJMoa1902226", &attbs))
 	if strings.Contains(url, "www.nejm.org") {
 		err = cdp.Run(ctx, visibleNejm(url, &attbs))
 	} else if stringo.StrDetect(url, "sciencedirect.com|/10.1016/|www.cell.com") {
@@ -38,8 +69,25 @@ func DoiSupplURLs(url string, timeout time.Duration, proxy string) []string {
 		err = cdp.Run(ctx, visibleSraRunSelect(url, &attbs, ctx))
 	}
 	if err != nil {
-		log.Fatal(err)
+		return urls, err
 	}
+	return attbsURLs(attbs), nil
+}
+
+// PageLinks render url and return the links and meta of the page
+func PageLinks(url string, timeout time.Duration, proxy string) (links []string, meta map[string]string, err error) {
+	ctx, cancel := newContext(timeout, proxy)
+	defer cancel()
+	err = cdp.Run(ctx, cdp.Tasks{
+		cdp.Navigate(url),
+		cdp.WaitReady("body"),
+		cdp.Evaluate(`Array.from(document.querySelectorAll('a[href]')).map(a => a.href)`, &links),
+		cdp.Evaluate(`Object.fromEntries(Array.from(document.querySelectorAll('meta[name^="citation_"]')).map(m => [m.name, m.content]))`, &meta),
+	})
+	return links, meta, err
+}
+
+func attbsURLs(attbs []map[string]string) (urls []string) {
 	for i := range attbs {
 		for k, v := range attbs[i] {
 			if k == "href" {
@@ -51,6 +99,7 @@ func DoiSupplURLs(url string, timeout time.Duration, proxy string) []string {
 	}
 	return urls
 }
+
 func visibleScienceDirect(host string, attbs *[]map[string]string) cdp.Tasks {
 	return cdp.Tasks{
 		cdp.Navigate(host),
@@ -130,47 +179,17 @@ func visibleWiley(url string, ctx context.Context) cdp.Tasks {
 	return tsk
 }
 
-func GetURLFile(url string, timeout time.Duration, proxy string) {
-	// create context
-	o := append(cdp.DefaultExecAllocatorOptions[:],
-		//... any options here
-		cdp.ProxyServer(proxy),
-	)
-	cx, cancel := cdp.NewExecAllocator(context.Background(), o...)
-	ctx, cancel := cdp.NewContext(cx)
-	ctx, cancel = context.WithTimeout(ctx, timeout)
+// GetURLFile render url with the browser
+func GetURLFile(url string, timeout time.Duration, proxy string) error {
+	ctx, cancel := newContext(timeout, proxy)
 	defer cancel()
-	var err error
-	var attbs []map[string]string
-	urls := []string{}
-	err = cdp.Run(ctx, visibleDownloadTask(url, ctx))
-	if err != nil {
-		log.Fatal(err)
-	}
-	for i := range attbs {
-		for k, v := range attbs[i] {
-			if k == "href" {
-				urls = append(urls, v)
-			} else if strings.Contains(k, "http") {
-				urls = append(urls, v)
-			}
-		}
-	}
+	return cdp.Run(ctx, visibleDownloadTask(url, ctx))
 }
 
 func visibleDownloadTask(url string, ctx context.Context) cdp.Tasks {
-	var body string
 	tsk := cdp.Tasks{
 		cdp.Navigate(url),
-		cdp.ActionFunc(func(context.Context) error {
-			return nil
-		}),
 		cdp.WaitVisible(`#main-container`, cdp.ByQuery),
-		chromedp.OuterHTML("html", &body),
-		cdp.ActionFunc(func(context.Context) error {
-			fmt.Println(body)
-			return nil
-		}),
 		cdp.WaitReady("body"),
 	}
 	return tsk
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/openanno/bget/api/fetch"
+	"github.com/openanno/bget/cassette"
+	"github.com/openanno/bget/spider"
+	cio "github.com/openbiox/ligo/io"
+	cnet "github.com/openbiox/ligo/net"
+)
+
+var extractAccessions bool
+var fetchAccessions bool
+var accessionsFullText bool
+
+// doiAccession is a data or code accession cited by a paper
+type doiAccession struct {
+	Type      string
+	Accession string
+	Source    string
+}
+
+// accessionPatterns is the patterns of accessions, the first group is the
+// accession if the pattern has groups
+var accessionPatterns = []struct {
+	Type    string
+	Pattern *regexp.Regexp
+}{
+	{"geo", regexp.MustCompile(`\bGSE[0-9]{3,}\b`)},
+	{"sra", regexp.MustCompile(`\b[SED]R[PXSR][0-9]{5,}\b`)},
+	{"bioproject", regexp.MustCompile(`\bPRJ(?:NA|EB|DB)[0-9]{3,}\b`)},
+	{"ega", regexp.MustCompile(`\bEGA[DS][0-9]{11}\b`)},
+	{"arrayexpress", regexp.MustCompile(`\bE-[A-Z]{4}-[0-9]+\b`)},
+	{"zenodo", regexp.MustCompile(`(?i)\b(10[.]5281/zenodo[.][0-9]+)\b`)},
+	{"github", regexp.MustCompile(`(?i)github[.]com/([A-Za-z0-9][A-Za-z0-9-]*/[A-Za-z0-9._-]+)`)},
+}
+
+// githubReserved is the github.com paths that are not repositories
+var githubReserved = map[string]bool{"about": true, "features": true, "login": true, "join": true,
+	"orgs": true, "site": true, "sponsors": true, "topics": true, "marketplace": true, "settings": true}
+
+// findAccessions return the unique accessions in text
+func findAccessions(text string, source string) (accessions []doiAccession) {
+	seen := make(map[string]bool)
+	for _, p := range accessionPatterns {
+		for _, m := range p.Pattern.FindAllStringSubmatch(text, -1) {
+			acc := m[len(m)-1]
+			if p.Type == "github" {
+				acc = strings.TrimSuffix(strings.TrimRight(acc, "._-"), ".git")
+				if githubReserved[strings.ToLower(strings.Split(acc, "/")[0])] {
+					continue
+				}
+			}
+			if p.Type == "zenodo" {
+				acc = strings.ToLower(acc)
+			}
+			if seen[p.Type+acc] {
+				continue
+			}
+			seen[p.Type+acc] = true
+			accessions = append(accessions, doiAccession{Type: p.Type, Accession: acc, Source: source})
+		}
+	}
+	return accessions
+}
+
+// dataAvailabilityRe matches the headings of data availability sections
+var dataAvailabilityRe = regexp.MustCompile(`(?i)(data|code|software|materials)\b.{0,30}\bavailab|availability of .{0,30}data|accession (codes|numbers)|data deposition`)
+
+// dataAvailabilityText return the text of the data availability sections in
+// the landing page or JATS XML, references are not scanned
+func dataAvailabilityText(body string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var texts []string
+	doc.Find("sec[sec-type*=data-availability], notes[notes-type*=data-availability]").Each(func(i int, s *goquery.Selection) {
+		texts = append(texts, spacedText(s))
+	})
+	doc.Find("section[data-title]").Each(func(i int, s *goquery.Selection) {
+		if dataAvailabilityRe.MatchString(s.AttrOr("data-title", "")) {
+			texts = append(texts, spacedText(s))
+		}
+	})
+	headings := "h1, h2, h3, h4, h5, h6, title"
+	doc.Find(headings + ", strong, b, dt").Each(func(i int, s *goquery.Selection) {
+		heading := strings.TrimSpace(spacedText(s))
+		if len(heading) > 80 || !dataAvailabilityRe.MatchString(heading) {
+			return
+		}
+		// the sections without a wrapper end at the next heading
+		if parent := s.Parent(); parent.Find(headings).Length() > 1 {
+			texts = append(texts, spacedText(s.NextUntil(headings)))
+		} else {
+			texts = append(texts, spacedText(parent))
+		}
+	})
+	return strings.Join(texts, "\n")
+}
+
+// spacedText return the text of s, the text nodes are separated by space
+// so that accessions are not glued to the headings
+func spacedText(s *goquery.Selection) string {
+	var parts []string
+	s.Contents().Each(func(i int, c *goquery.Selection) {
+		if goquery.NodeName(c) == "#text" {
+			parts = append(parts, c.Text())
+		} else {
+			parts = append(parts, spacedText(c))
+		}
+	})
+	return strings.Join(parts, " ")
+}
+
+// extractDoiAccessions scan the data availability sections of the landing
+// page and the PMC XML of DOI for accessions, or the whole text with
+// --accessions-full-text
+func extractDoiAccessions(doi string, opt *spider.DoiSpiderOpt) (accessions []doiAccession) {
+	seen := make(map[string]bool)
+	add := func(accs []doiAccession) {
+		for _, v := range accs {
+			if !seen[v.Type+v.Accession] {
+				seen[v.Type+v.Accession] = true
+				accessions = append(accessions, v)
+			}
+		}
+	}
+	if opt != nil && opt.URL != nil {
+		if body, err := fetchHTML(opt.URL.String()); err != nil {
+			log.Warnf("Landing page of %s: %v", doi, err)
+		} else {
+			add(findAccessions(accessionText(body), "html"))
+		}
+	}
+	bapiClis := setBapiClis()
+	records, err := fetch.NcbiIDConv([]string{doi}, bapiClis)
+	if err != nil {
+		log.Warnln(err)
+	}
+	for _, r := range records {
+		if r.Pmcid == "" {
+			continue
+		}
+		xml, err := fetch.EuropePmcFullTextXML(string(r.Pmcid), bapiClis)
+		if err != nil {
+			log.Warnln(err)
+			continue
+		}
+		add(findAccessions(accessionText(string(xml)), "pmc"))
+	}
+	return accessions
+}
+
+func accessionText(body string) string {
+	if accessionsFullText {
+		return body
+	}
+	return dataAvailabilityText(body)
+}
+
+func fetchHTML(url string) (string, error) {
+	client := cassette.NewHTTPClient(bgetClis.Timeout, bgetClis.Proxy)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	cnet.SetDefaultReqHeader(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	buf, err := ioutil.ReadAll(resp.Body)
+	return string(buf), err
+}
+
+func (task *doiTask) writeAccessions() {
+	outfn := task.metaFile("accessions.tsv")
+	lines := []string{"doi\ttype\taccession\tsource"}
+	for _, v := range task.Accessions {
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s", task.Doi, v.Type, v.Accession, v.Source))
+	}
+	log.Infof("Finding %d accessions of %s => %s", len(task.Accessions), task.Doi, outfn)
+	cio.CreateFileParDir(outfn)
+	if err := ioutil.WriteFile(outfn, []byte(strings.Join(lines, "\n")+"\n"), 0664); err != nil {
+		log.Warnln(err)
+	}
+}
+
+// downloadAccessions hand the accessions to bget seq and bget url --github
+func downloadAccessions(tasks map[string]*doiTask) {
+	var seqs, repos, skipped []string
+	seen := make(map[string]bool)
+	for _, task := range tasks {
+		for _, v := range task.Accessions {
+			if seen[v.Accession] {
+				continue
+			}
+			seen[v.Accession] = true
+			switch {
+			case seqAccessionSupported(v.Accession):
+				seqs = append(seqs, v.Accession)
+			case v.Type == "github":
+				repos = append(repos, v.Accession)
+			default:
+				skipped = append(skipped, v.Accession)
+			}
+		}
+	}
+	if len(skipped) > 0 {
+		log.Infof("Skipping accessions not supported by bget seq: %s", strings.Join(skipped, ", "))
+	}
+	if len(seqs) > 0 {
+		bgetClis.Seqs = strings.Join(seqs, bgetClis.Seperator)
+		downloadSeq()
+	}
+	if len(repos) > 0 {
+		bgetClis.GitHub = strings.Join(repos, bgetClis.Seperator)
+		downloadGitHubRepos()
+	}
+}
+
+// seqAccessionSupported indicates whether acc can be downloaded by bget seq
+func seqAccessionSupported(acc string) bool {
+	if isInsdcRun(acc) || isInsdcExpandable(acc) {
+		return true
+	}
+	for _, v := range []string{"GSE", "EGAD"} {
+		if strings.HasPrefix(acc, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindAccessions(t *testing.T) {
+	text := `Data are available in GEO (GSE123456) and SRA (SRP098765, PRJNA512345).
+Controlled data: EGAD00001000951. Arrays: E-MTAB-6701. Code: https://github.com/openanno/bget.git
+and doi:10.5281/zenodo.3363060. See also https://github.com/about and GSE123456.`
+	want := map[string]string{
+		"GSE123456":              "geo",
+		"SRP098765":              "sra",
+		"PRJNA512345":            "bioproject",
+		"EGAD00001000951":        "ega",
+		"E-MTAB-6701":            "arrayexpress",
+		"10.5281/zenodo.3363060": "zenodo",
+		"openanno/bget":          "github",
+	}
+	accessions := findAccessions(text, "html")
+	if len(accessions) != len(want) {
+		t.Fatalf("unexpected accessions: %+v", accessions)
+	}
+	for _, v := range accessions {
+		if want[v.Accession] != v.Type {
+			t.Errorf("unexpected accession: %+v", v)
+		}
+	}
+}
+
+func TestDataAvailabilityText(t *testing.T) {
+	for name, c := range map[string]struct {
+		body string
+		want string
+	}{
+		"wrapped": {`<section data-title="Data availability"><h2>Data availability</h2><p>Reads: GSE111111.</p></section>
+<section data-title="References"><p>Ref GSE222222</p></section>`, "GSE111111"},
+		"flat":   {`<h2>Methods</h2><p>GSE222222</p><h2>Data and code availability</h2><p>See SRP098765.</p><h2>References</h2><p>GSE333333</p>`, "SRP098765"},
+		"inline": {`<p><strong>Accession codes:</strong> PRJNA512345</p><p>Cited GSE333333</p>`, "PRJNA512345"},
+		"jats": {`<article><body><sec sec-type="data-availability"><title>Data Availability</title><p>E-MTAB-6701</p></sec></body>
+<back><ref-list><ref>GSE444444</ref></ref-list></back></article>`, "E-MTAB-6701"},
+		"none": {`<p>GSE555555</p>`, ""},
+	} {
+		got := []string{}
+		for _, v := range findAccessions(dataAvailabilityText(c.body), "html") {
+			got = append(got, v.Accession)
+		}
+		if strings.Join(got, " ") != c.want {
+			t.Errorf("%s: unexpected accessions %v", name, got)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/openanno/bget/api/fetch"
+	cio "github.com/openbiox/ligo/io"
+	"github.com/openbiox/ligo/stringo"
+)
+
+// bibEntry is one entry of bibliography files
+type bibEntry struct {
+	Key    string
+	Type   string
+	Title  string
+	Year   string
+	Author string
+	Doi    string
+	Pmid   string
+	Pmcid  string
+	Arxiv  string
+	// ID is the identifier passed to the DOI spiders
+	ID     string
+	Source string
+}
+
+// bibFormat detect bibliography format from file suffix
+func bibFormat(fn string) string {
+	switch strings.ToLower(path.Ext(fn)) {
+	case ".bib", ".bibtex":
+		return "bibtex"
+	case ".ris":
+		return "ris"
+	case ".json":
+		return "csl-json"
+	case ".rdf":
+		return "zotero-rdf"
+	}
+	return ""
+}
+
+func parseBibFile(fn string) (entries []bibEntry, err error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch bibFormat(fn) {
+	case "bibtex":
+		return parseBibTeX(f)
+	case "ris":
+		return parseRIS(f)
+	case "csl-json":
+		return parseCslJSON(f)
+	case "zotero-rdf":
+		return parseZoteroRDF(f)
+	}
+	return nil, fmt.Errorf("unsupported bibliography file: %s", fn)
+}
+
+// parseBibTeX parse @type{key, field = {value}, ...} entries
+func parseBibTeX(r io.Reader) (entries []bibEntry, err error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	s := string(buf)
+	for {
+		start := strings.Index(s, "@")
+		if start < 0 {
+			break
+		}
+		s = s[start+1:]
+		open := strings.IndexAny(s, "{(")
+		if open < 0 {
+			break
+		}
+		entryType := strings.ToLower(strings.TrimSpace(s[0:open]))
+		body, rest := bibBlock(s[open:])
+		s = rest
+		if entryType == "comment" || entryType == "string" || entryType == "preamble" {
+			continue
+		}
+		fields := bibFields(body)
+		entry := bibEntry{
+			Key:    fields[""],
+			Type:   entryType,
+			Title:  fields["title"],
+			Year:   fields["year"],
+			Author: fields["author"],
+			Doi:    fields["doi"],
+			Pmid:   fields["pmid"],
+			Pmcid:  fields["pmcid"],
+		}
+		if entry.Year == "" && len(fields["date"]) >= 4 {
+			entry.Year = fields["date"][0:4]
+		}
+		if strings.EqualFold(fields["archiveprefix"], "arxiv") || strings.EqualFold(fields["eprinttype"], "arxiv") {
+			entry.Arxiv = fields["eprint"]
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// bibBlock return the content of balanced braces/parentheses and the remaining text
+func bibBlock(s string) (body string, rest string) {
+	opening := s[0]
+	closing := byte('}')
+	if opening == '(' {
+		closing = ')'
+	}
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == opening || (opening == '(' && s[i] == '{') {
+			depth++
+		} else if s[i] == closing || (opening == '(' && s[i] == '}') {
+			depth--
+			if depth == 0 {
+				return s[1:i], s[i+1:]
+			}
+		}
+	}
+	return s[1:], ""
+}
+
+// bibFields parse 'key, field = {value}, field = "value"' into a map, the citation key is stored in ""
+func bibFields(body string) map[string]string {
+	fields := make(map[string]string)
+	comma := strings.Index(body, ",")
+	if comma < 0 {
+		fields[""] = strings.TrimSpace(body)
+		return fields
+	}
+	fields[""] = strings.TrimSpace(body[0:comma])
+	s := body[comma+1:]
+	for {
+		eq := strings.Index(s, "=")
+		if eq < 0 {
+			break
+		}
+		name := strings.ToLower(strings.TrimSpace(strings.Trim(s[0:eq], ", \t\r\n")))
+		s = strings.TrimLeft(s[eq+1:], " \t\r\n")
+		var value string
+		if strings.HasPrefix(s, "{") {
+			value, s = bibBlock(s)
+		} else if strings.HasPrefix(s, `"`) {
+			end := strings.Index(s[1:], `"`)
+			if end < 0 {
+				value, s = s[1:], ""
+			} else {
+				value, s = s[1:end+1], s[end+2:]
+			}
+		} else {
+			end := strings.Index(s, ",")
+			if end < 0 {
+				value, s = s, ""
+			} else {
+				value, s = s[0:end], s[end+1:]
+			}
+		}
+		value = strings.NewReplacer("{", "", "}", "", "\n", " ", "\t", " ").Replace(value)
+		fields[name] = strings.TrimSpace(stringo.StrReplaceAll(value, " +", " "))
+	}
+	return fields
+}
+
+// parseRIS parse 'TAG  - value' records ended by 'ER  -'
+func parseRIS(r io.Reader) (entries []bibEntry, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	entry := bibEntry{}
+	n := 0
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) < 5 || line[2:5] != "  -" {
+			continue
+		}
+		tag := line[0:2]
+		value := strings.TrimSpace(line[5:])
+		switch tag {
+		case "TY":
+			entry = bibEntry{Type: value}
+		case "ID":
+			entry.Key = value
+		case "TI", "T1":
+			if entry.Title == "" {
+				entry.Title = value
+			}
+		case "PY", "Y1", "DA":
+			if entry.Year == "" && len(value) >= 4 {
+				entry.Year = value[0:4]
+			}
+		case "AU", "A1":
+			if entry.Author == "" {
+				entry.Author = value
+			} else {
+				entry.Author = entry.Author + " and " + value
+			}
+		case "DO":
+			entry.Doi = value
+		case "ER":
+			n++
+			if entry.Key == "" {
+				entry.Key = strconv.Itoa(n)
+			}
+			entries = append(entries, entry)
+			entry = bibEntry{}
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// cslItem is the CSL-JSON item
+type cslItem struct {
+	ID     interface{} `json:"id"`
+	Type   string      `json:"type"`
+	Title  string      `json:"title"`
+	DOI    string      `json:"DOI"`
+	PMID   string      `json:"PMID"`
+	PMCID  string      `json:"PMCID"`
+	Issued struct {
+		DateParts [][]interface{} `json:"date-parts"`
+	} `json:"issued"`
+	Author []struct {
+		Family string `json:"family"`
+		Given  string `json:"given"`
+	} `json:"author"`
+}
+
+func parseCslJSON(r io.Reader) (entries []bibEntry, err error) {
+	items := []cslItem{}
+	if err = json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, err
+	}
+	for _, v := range items {
+		entry := bibEntry{
+			Key:   fmt.Sprint(v.ID),
+			Type:  v.Type,
+			Title: v.Title,
+			Doi:   v.DOI,
+			Pmid:  v.PMID,
+			Pmcid: v.PMCID,
+		}
+		if len(v.Issued.DateParts) > 0 && len(v.Issued.DateParts[0]) > 0 {
+			entry.Year = fmt.Sprint(v.Issued.DateParts[0][0])
+		}
+		authors := []string{}
+		for _, a := range v.Author {
+			authors = append(authors, strings.TrimSpace(a.Family+", "+a.Given))
+		}
+		entry.Author = strings.Join(authors, " and ")
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseZoteroRDF parse the direct children (dc:title, dc:date, dc:identifier) of Zotero RDF items
+func parseZoteroRDF(r io.Reader) (entries []bibEntry, err error) {
+	dec := xml.NewDecoder(r)
+	depth := 0
+	var entry *bibEntry
+	var field string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return entries, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 {
+				entry = &bibEntry{Type: t.Name.Local}
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "about" {
+						entry.Key = attr.Value
+					}
+				}
+			} else if depth == 3 && entry != nil {
+				field = t.Name.Local
+			} else if depth > 3 && field == "identifier" && t.Name.Local == "value" {
+				// <dc:identifier><dcterms:URI><rdf:value>...</rdf:value></dcterms:URI></dc:identifier>
+				field = "identifier-value"
+			}
+		case xml.CharData:
+			if entry == nil {
+				continue
+			}
+			value := strings.TrimSpace(string(t))
+			if value == "" {
+				continue
+			}
+			switch {
+			case depth == 3 && field == "title":
+				entry.Title = value
+			case depth == 3 && field == "date":
+				if year := stringo.StrExtract(value, "[0-9]{4}", 1); len(year) > 0 {
+					entry.Year = year[0]
+				}
+			case (depth == 3 && field == "identifier") || field == "identifier-value":
+				zoteroIdentifier(entry, value)
+			}
+		case xml.EndElement:
+			if depth == 2 && entry != nil {
+				if entry.Title != "" || entry.Doi != "" {
+					entries = append(entries, *entry)
+				}
+				entry = nil
+			} else if depth == 3 {
+				field = ""
+			}
+			depth--
+		}
+	}
+	return entries, nil
+}
+
+func zoteroIdentifier(entry *bibEntry, value string) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(strings.ToUpper(value), "DOI ") {
+		entry.Doi = strings.TrimSpace(value[4:])
+	} else if stringo.StrDetect(value, "^https?://(dx[.])?doi[.]org/") {
+		entry.Doi = stringo.StrReplaceAll(value, "^https?://(dx[.])?doi[.]org/", "")
+	}
+}
+
+// resolveBibEntries set the identifier of each entry, entries without
+// any identifier are searched by title and year in Crossref
+func resolveBibEntries(entries []bibEntry) {
+	bapiClis := setBapiClis()
+	for i := range entries {
+		e := &entries[i]
+		switch {
+		case e.Doi != "":
+			e.ID, e.Source = e.Doi, "doi"
+		case e.Pmid != "":
+			e.ID, e.Source = "pmid:"+e.Pmid, "pmid"
+		case e.Pmcid != "":
+			e.ID, e.Source = e.Pmcid, "pmcid"
+		case e.Arxiv != "":
+			e.ID, e.Source = "arXiv:"+e.Arxiv, "arxiv"
+		case e.Title != "":
+			filter := ""
+			if e.Year != "" {
+				filter = fmt.Sprintf("from-pub-date:%s,until-pub-date:%s", e.Year, e.Year)
+			}
+			items, err := fetch.CrossRefQuery(e.Title, filter, 3, bapiClis)
+			if err != nil {
+				log.Warnln(err)
+			}
+			for _, v := range items {
+				if len(v.Title) > 0 && titleMatched(v.Title[0], e.Title) {
+					log.Infof("Matching %s (%s) => %s", e.Key, e.Title, v.DOI)
+					e.ID, e.Source = v.DOI, "crossref-title"
+					break
+				}
+			}
+		}
+		if e.ID == "" {
+			e.Source = "unresolved"
+			log.Warnf("Can not resolve DOI of %s: %s", e.Key, e.Title)
+		}
+	}
+}
+
+func titleMatched(a, b string) bool {
+	var normalize = func(s string) string {
+		return strings.ToLower(stringo.StrReplaceAll(s, `[^\p{L}\p{N}]+`, ""))
+	}
+	a, b = normalize(a), normalize(b)
+	return a != "" && b != "" && (a == b || strings.Contains(a, b) || strings.Contains(b, a))
+}
+
+// outcome return no-candidates, failed or downloaded
+func (task *doiTask) outcome() string {
+	if len(task.URLs) == 0 && len(task.SupplURLs) == 0 && task.Dataset == nil && len(task.Files) == 0 {
+		return "no-candidates"
+	} else if len(task.Files) == 0 {
+		return "failed"
+	}
+	return "downloaded"
+}
+
+// writeBibReport write the download outcome of each bibliography entry
+func writeBibReport(entries []bibEntry, idMap map[string]string, tasks map[string]*doiTask) {
+	outfn := path.Join(bgetClis.DownloadDir, "bibliography.report.tsv")
+	cio.CreateFileParDir(outfn)
+	of, err := os.Create(outfn)
+	if err != nil {
+		log.Warnln(err)
+		return
+	}
+	defer of.Close()
+	fmt.Fprintln(of, strings.Join([]string{"key", "type", "title", "year", "doi", "source", "outcome", "status", "files"}, "\t"))
+	for _, e := range entries {
+		doi := idMap[e.ID]
+		outcome := "unresolved"
+		status := ""
+		files := []string{}
+		if e.ID != "" && doi == "" {
+			outcome = "unconvertible"
+		} else if task, ok := tasks[doi]; ok {
+			files = task.Files
+			status = task.Status
+			outcome = task.outcome()
+		}
+		fmt.Fprintln(of, strings.Join([]string{e.Key, e.Type, e.Title, e.Year, doi, e.Source,
+			outcome, status, strings.Join(files, ";")}, "\t"))
+	}
+	log.Infof("Saving bibliography report => %s", outfn)
+}
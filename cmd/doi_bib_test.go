@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBibTeX(t *testing.T) {
+	bib := `@article{Jia2019,
+  title = {Galectins control {MTOR} and {AMPK}},
+  author = {Jia, Jingyue and Deretic, Vojo},
+  year = 2019,
+  doi = {10.1073/pnas.1814397115}
+}
+@misc{arxiv2020, title="A preprint", eprint="2001.01234", archivePrefix="arXiv"}`
+	entries, err := parseBibTeX(strings.NewReader(bib))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Key != "Jia2019" || entries[0].Doi != "10.1073/pnas.1814397115" || entries[0].Year != "2019" {
+		t.Errorf("unexpected entry: %+v", entries[0])
+	}
+	if entries[1].Arxiv != "2001.01234" {
+		t.Errorf("unexpected entry: %+v", entries[1])
+	}
+}
+
+func TestParseRIS(t *testing.T) {
+	ris := `TY  - JOUR
+TI  - Galectins control MTOR and AMPK
+PY  - 2019
+DO  - 10.1073/pnas.1814397115
+ER  -
+TY  - JOUR
+TI  - Another paper
+AN  - 30487223
+ER  -
+`
+	entries, err := parseRIS(strings.NewReader(ris))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Doi != "10.1073/pnas.1814397115" || entries[0].Year != "2019" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestParseCslJSON(t *testing.T) {
+	csl := `[{"id": "item1", "type": "article-journal", "title": "Galectins",
+	"DOI": "10.1073/pnas.1814397115", "PMID": "30487223",
+	"issued": {"date-parts": [[2019, 1, 2]]}}]`
+	entries, err := parseCslJSON(strings.NewReader(csl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Doi != "10.1073/pnas.1814397115" || entries[0].Pmid != "30487223" || entries[0].Year != "2019" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestParseZoteroRDF(t *testing.T) {
+	rdf := `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+ xmlns:z="http://www.zotero.org/namespaces/export#"
+ xmlns:dc="http://purl.org/dc/elements/1.1/"
+ xmlns:bib="http://purl.org/net/biblio#">
+  <bib:Article rdf:about="#item_1">
+    <z:itemType>journalArticle</z:itemType>
+    <dc:title>Galectins control MTOR and AMPK</dc:title>
+    <dc:date>2019-01-02</dc:date>
+    <dc:identifier>DOI 10.1073/pnas.1814397115</dc:identifier>
+  </bib:Article>
+</rdf:RDF>`
+	entries, err := parseZoteroRDF(strings.NewReader(rdf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Doi != "10.1073/pnas.1814397115" || entries[0].Year != "2019" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	neturl "net/url"
+	"os"
+	"os/user"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/openanno/bget/spider"
+	cio "github.com/openbiox/ligo/io"
+)
+
+var doiCacheDir string
+var doiCacheTTL int
+var refreshDoiCache bool
+
+// doiCacheEntry is the resolution result of a DOI cached on disk
+type doiCacheEntry struct {
+	Doi           string                `json:"doi"`
+	Landing       string                `json:"landing"`
+	Spiders       []string              `json:"spiders"`
+	FullText      bool                  `json:"full_text"`
+	Supplementary bool                  `json:"supplementary"`
+	Pmc           bool                  `json:"pmc"`
+	Scihub        bool                  `json:"scihub"`
+	Browser       string                `json:"browser,omitempty"`
+	Candidates    []spider.DoiCandidate `json:"candidates"`
+	CitationMeta  map[string]string     `json:"citation_meta"`
+	Date          time.Time             `json:"date"`
+}
+
+func defaultDoiCacheDir() string {
+	us, err := user.Current()
+	if err != nil {
+		return path.Join(os.TempDir(), "bget", "cache", "doi")
+	}
+	return path.Join(us.HomeDir, ".config", "bget", "cache", "doi")
+}
+
+// setDoiCacheDir resolve the default cache dir, it is called before the
+// DOIs are resolved concurrently
+func setDoiCacheDir() {
+	if doiCacheDir == "" {
+		doiCacheDir = defaultDoiCacheDir()
+	}
+}
+
+func doiCacheFile(doi string) string {
+	return path.Join(doiCacheDir, sanitizeFilename(strings.ToLower(doi))+".json")
+}
+
+// loadDoiCache restore the cached resolution of opt.Doi into opt, nil is
+// returned if the entry is missing, expired or resolved with other options
+func loadDoiCache(opt *spider.DoiSpiderOpt) *doiCacheEntry {
+	if doiCacheTTL <= 0 || refreshDoiCache {
+		return nil
+	}
+	buf, err := ioutil.ReadFile(doiCacheFile(opt.Doi))
+	if err != nil {
+		return nil
+	}
+	entry := doiCacheEntry{}
+	if err = json.Unmarshal(buf, &entry); err != nil {
+		log.Warnf("Invalid cache of %s: %v", opt.Doi, err)
+		return nil
+	}
+	if time.Since(entry.Date) > time.Duration(doiCacheTTL)*time.Hour ||
+		entry.FullText != opt.FullText || entry.Supplementary != opt.Supplementary ||
+		entry.Pmc != pmc || entry.Scihub != scihub || entry.Browser != browser ||
+		len(entry.Candidates) == 0 {
+		return nil
+	}
+	if entry.Landing != "" {
+		opt.URL, _ = neturl.Parse(entry.Landing)
+	}
+	if opt.CitationMeta != nil {
+		for k, v := range entry.CitationMeta {
+			(*opt.CitationMeta)[k] = v
+		}
+	}
+	for i := range entry.Candidates {
+		entry.Candidates[i].Selected = false
+	}
+	log.Infof("Using cached links of %s (%s, --refresh to re-resolve).", opt.Doi, entry.Date.Format(time.RFC3339))
+	return &entry
+}
+
+// saveDoiCache write the resolution of opt.Doi to the cache dir
+func saveDoiCache(opt *spider.DoiSpiderOpt, candidates []spider.DoiCandidate) {
+	if doiCacheTTL <= 0 || len(candidates) == 0 {
+		return
+	}
+	entry := doiCacheEntry{
+		Doi:           opt.Doi,
+		FullText:      opt.FullText,
+		Supplementary: opt.Supplementary,
+		Pmc:           pmc,
+		Scihub:        scihub,
+		Browser:       browser,
+		Candidates:    candidates,
+		Date:          time.Now(),
+	}
+	if opt.URL != nil {
+		entry.Landing = opt.URL.String()
+	}
+	if opt.CitationMeta != nil {
+		entry.CitationMeta = *opt.CitationMeta
+	}
+	sources := make(map[string]bool)
+	for _, c := range candidates {
+		if !sources[c.Source] {
+			sources[c.Source] = true
+			entry.Spiders = append(entry.Spiders, c.Source)
+		}
+	}
+	buf, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		log.Warnln(err)
+		return
+	}
+	outfn := doiCacheFile(opt.Doi)
+	cio.CreateFileParDir(outfn)
+	if err = ioutil.WriteFile(outfn+".tmp", buf, 0664); err != nil {
+		log.Warnln(err)
+		return
+	}
+	if err = os.Rename(outfn+".tmp", outfn); err != nil {
+		log.Warnln(err)
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/openanno/bget/spider"
+)
+
+func TestDoiCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bget-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	doiCacheDir, doiCacheTTL = dir, 1
+	defer func() { doiCacheDir, doiCacheTTL = "", 168 }()
+
+	meta := map[string]string{"citation_title": "A paper"}
+	opt := &spider.DoiSpiderOpt{Doi: "10.1038/s41586-019-1844-5", FullText: true, CitationMeta: &meta}
+	saveDoiCache(opt, []spider.DoiCandidate{{URL: "https://a.org/main.pdf", Source: "publisher", Selected: true}})
+
+	restored := make(map[string]string)
+	opt2 := &spider.DoiSpiderOpt{Doi: opt.Doi, FullText: true, CitationMeta: &restored}
+	entry := loadDoiCache(opt2)
+	if entry == nil || len(entry.Candidates) != 1 || entry.Candidates[0].Selected || restored["citation_title"] != "A paper" {
+		t.Fatalf("unexpected cache entry: %+v", entry)
+	}
+	opt2.Supplementary = true
+	if loadDoiCache(opt2) != nil {
+		t.Error("cache resolved without supplementary files should be ignored")
+	}
+	opt2.Supplementary = false
+	pmc = true
+	defer func() { pmc = false }()
+	if loadDoiCache(opt2) != nil {
+		t.Error("cache resolved without PMC should be ignored")
+	}
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/openanno/bget/api/fetch"
+	"github.com/openanno/bget/api/types"
+	"github.com/openanno/bget/spider"
+	cio "github.com/openbiox/ligo/io"
+)
+
+var datasetVersion string
+
+var datasetDoiPatterns = []struct {
+	Repository string
+	Pattern    *regexp.Regexp
+}{
+	{"zenodo", regexp.MustCompile(`(?i)^10[.]5281/zenodo[.]([0-9]+)$`)},
+	{"figshare", regexp.MustCompile(`(?i)^10[.]6084/m9[.]figshare[.]([0-9]+)(?:[.]v([0-9]+))?$`)},
+	{"dryad", regexp.MustCompile(`(?i)^(10[.]5061/dryad[.][a-z0-9]+)$`)},
+	{"osf", regexp.MustCompile(`(?i)^10[.]17605/osf[.]io/([a-z0-9]+)$`)},
+}
+
+// parseDatasetDoi return the repository, record ID and version of a
+// dataset DOI (Zenodo, Figshare, Dryad and OSF)
+func parseDatasetDoi(doi string) (repository string, id string, version string) {
+	for _, v := range datasetDoiPatterns {
+		if m := v.Pattern.FindStringSubmatch(strings.TrimSpace(doi)); m != nil {
+			if len(m) > 2 {
+				version = m[2]
+			}
+			return v.Repository, m[1], version
+		}
+	}
+	return "", "", ""
+}
+
+// newDataset list the files of dataset DOI via repository API, nil is
+// returned for other DOIs or if the API fails
+func newDataset(doi string) *types.DatasetRecord {
+	repository, id, version := parseDatasetDoi(doi)
+	if repository == "" {
+		return nil
+	}
+	if datasetVersion != "" {
+		version = datasetVersion
+	}
+	var record *types.DatasetRecord
+	var err error
+	bapiClis := setBapiClis()
+	switch repository {
+	case "zenodo":
+		record, err = fetch.ZenodoDataset(id, version, bapiClis)
+	case "figshare":
+		record, err = fetch.FigshareDataset(id, version, bapiClis)
+	case "dryad":
+		record, err = fetch.DryadDataset(id, version, bapiClis)
+	case "osf":
+		record, err = fetch.OsfDataset(id, bapiClis)
+	}
+	if err != nil || record == nil || len(record.Files) == 0 {
+		log.Warnf("%s API of %s: %v, trying the spiders.", repository, doi, err)
+		return nil
+	}
+	log.Infof("Finding %d files of %s (%s record %s, version %s).", len(record.Files), doi, repository, record.ID, record.Version)
+	return record
+}
+
+// datasetCandidates return the files of record as candidates
+func datasetCandidates(record *types.DatasetRecord) (candidates []spider.DoiCandidate) {
+	for _, f := range record.Files {
+		candidates = append(candidates, spider.DoiCandidate{URL: f.URL, Source: record.Repository,
+			Kind: spider.CandidateDataset, Score: 1, Selected: true, Label: f.Path})
+	}
+	return candidates
+}
+
+func (task *doiTask) datasetDir() string {
+	if task.Prefix != "" {
+		return path.Join(task.OutDir, task.Prefix)
+	}
+	if layout != "by-doi" {
+		return path.Join(task.OutDir, sanitizeFilename(task.Doi))
+	}
+	return task.OutDir
+}
+
+func (task *doiTask) datasetStageDir(i int) string {
+	return path.Join(task.OutDir, ".bget", sanitizeFilename(task.Doi)+"-files", strconv.Itoa(i))
+}
+
+// finalizeDataset verify the checksums of the downloaded files, move them
+// to the record paths and save record.json, files failed the checksum are
+// kept as .corrupt in the stage dir and recorded in task.Failed
+func (task *doiTask) finalizeDataset() {
+	if task.Dataset == nil {
+		return
+	}
+	for i := range task.Dataset.Files {
+		f := &task.Dataset.Files[i]
+		src := stagedFile(task.datasetStageDir(i))
+		if src == "" {
+			log.Warnf("%s of %s is not downloaded.", f.Path, task.Doi)
+			continue
+		}
+		if f.Checksum != "" {
+			verified, err := verifyChecksum(src, f.Checksum)
+			if err != nil {
+				log.Warnln(err)
+			} else if f.Verified = &verified; !verified {
+				log.Warnf("Checksum mismatch of %s (%s), kept as %s.corrupt.", f.Path, f.Checksum, src)
+				if err := os.Rename(src, src+".corrupt"); err != nil {
+					log.Warnln(err)
+				}
+				task.Failed = append(task.Failed, src+".corrupt")
+				continue
+			}
+		}
+		dest := path.Join(task.datasetDir(), path.Clean("/"+f.Path))
+		cio.CreateFileParDir(dest)
+		if err := os.Rename(src, dest); err != nil {
+			log.Warnln(err)
+			continue
+		}
+		task.Files = append(task.Files, dest)
+	}
+	if len(task.Failed) == 0 {
+		os.RemoveAll(path.Join(task.OutDir, ".bget", sanitizeFilename(task.Doi)+"-files"))
+		os.Remove(path.Join(task.OutDir, ".bget"))
+	}
+	buf, err := json.MarshalIndent(task.Dataset, "", "  ")
+	if err != nil {
+		log.Warnln(err)
+		return
+	}
+	outfn := task.metaFile("record.json")
+	if err = ioutil.WriteFile(outfn, buf, 0664); err != nil {
+		log.Warnln(err)
+	}
+}
+
+// stagedFile return the completely downloaded file in dir
+func stagedFile(dir string) string {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, f := range files {
+		if f.IsDir() || strings.HasSuffix(f.Name(), ".st") || strings.HasSuffix(f.Name(), ".corrupt") {
+			continue
+		}
+		if hasSt, _ := cio.PathExists(path.Join(dir, f.Name()+".st")); hasSt {
+			continue
+		}
+		return path.Join(dir, f.Name())
+	}
+	return ""
+}
+
+// verifyChecksum check fn against checksum (md5, sha1, sha256 or sha512), e.g. md5:abc
+func verifyChecksum(fn string, checksum string) (bool, error) {
+	kv := strings.SplitN(checksum, ":", 2)
+	if len(kv) != 2 {
+		return false, fmt.Errorf("invalid checksum %s", checksum)
+	}
+	var h hash.Hash
+	switch strings.ToLower(strings.ReplaceAll(kv[0], "-", "")) {
+	case "md5":
+		h = md5.New()
+	case "sha1":
+		h = sha1.New()
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return false, fmt.Errorf("unsupported checksum %s", checksum)
+	}
+	f, err := os.Open(fn)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	if _, err = io.Copy(h, f); err != nil {
+		return false, err
+	}
+	return strings.EqualFold(hex.EncodeToString(h.Sum(nil)), kv[1]), nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/openanno/bget/api/types"
+	cio "github.com/openbiox/ligo/io"
+)
+
+func TestParseDatasetDoi(t *testing.T) {
+	for doi, want := range map[string][3]string{
+		"10.5281/zenodo.3363060":         {"zenodo", "3363060", ""},
+		"10.6084/m9.figshare.9332999.v2": {"figshare", "9332999", "2"},
+		"10.5061/dryad.2bvq83bmf":        {"dryad", "10.5061/dryad.2bvq83bmf", ""},
+		"10.17605/OSF.IO/XQ9Z4":          {"osf", "XQ9Z4", ""},
+		"10.1038/s41586-019-1844-5":      {"", "", ""},
+	} {
+		repository, id, version := parseDatasetDoi(doi)
+		if repository != want[0] || id != want[1] || version != want[2] {
+			t.Errorf("unexpected result of %s: %s %s %s", doi, repository, id, version)
+		}
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	f, err := ioutil.TempFile("", "bget-checksum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("bget\n")
+	f.Close()
+	for checksum, want := range map[string]bool{
+		"md5:c6b3e1a3f35b7a1c1b2fa1bcdf3ba3d9": false,
+		"md5:E5059ED5026279463CB3E6AD8BCE7E9D": true,
+		"sha256:00":                            false,
+	} {
+		if ok, err := verifyChecksum(f.Name(), checksum); err != nil || ok != want {
+			t.Errorf("unexpected result of %s: %v %v", checksum, ok, err)
+		}
+	}
+	if _, err := verifyChecksum(f.Name(), "crc32:abc"); err == nil {
+		t.Error("unsupported checksum should fail")
+	}
+}
+
+func TestFinalizeDataset(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bget-dataset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	task := &doiTask{Doi: "10.5281/zenodo.1", OutDir: dir, Prefix: "zenodo",
+		Dataset: &types.DatasetRecord{Files: []types.DatasetFile{
+			{Path: "good.txt", Checksum: "md5:e5059ed5026279463cb3e6ad8bce7e9d"},
+			{Path: "bad.txt", Checksum: "md5:c6b3e1a3f35b7a1c1b2fa1bcdf3ba3d9"},
+		}}}
+	for i, f := range task.Dataset.Files {
+		os.MkdirAll(task.datasetStageDir(i), 0755)
+		ioutil.WriteFile(path.Join(task.datasetStageDir(i), f.Path), []byte("bget\n"), 0644)
+	}
+	task.finalizeDataset()
+	if len(task.Files) != 1 || task.Files[0] != path.Join(dir, "zenodo", "good.txt") {
+		t.Errorf("unexpected files: %v", task.Files)
+	}
+	if len(task.Failed) != 1 || task.Failed[0] != path.Join(task.datasetStageDir(1), "bad.txt.corrupt") {
+		t.Errorf("unexpected failed files: %v", task.Failed)
+	}
+	if ok, _ := cio.PathExists(path.Join(dir, "zenodo", "bad.txt")); ok {
+		t.Error("corrupt file should not be moved to the dataset dir")
+	}
+}
@@ -0,0 +1,449 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"os/user"
+	"path"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/openanno/bget/cassette"
+	"github.com/openanno/bget/spider"
+	cio "github.com/openbiox/ligo/io"
+	cnet "github.com/openbiox/ligo/net"
+	"github.com/spf13/cobra"
+)
+
+// DiagnoseFixturesURL is the remote fixture list of bget doi diagnose
+const DiagnoseFixturesURL = "https://raw.githubusercontent.com/openanno/bget/master/_meta/doi/diagnose.json"
+
+var diagnoseFixtures string
+var fixturesDir string
+var saveFixtures bool
+var offline bool
+
+// diagnoseFixture is the sample DOI of one prefix
+type diagnoseFixture struct {
+	Prefix string `json:"prefix"`
+	Doi    string `json:"doi"`
+}
+
+// doiDiagnosis is the health report of the spider chain for one DOI
+type doiDiagnosis struct {
+	Prefix        string              `json:"prefix"`
+	Doi           string              `json:"doi"`
+	Spider        string              `json:"spider"`
+	URL           string              `json:"url"`
+	Status        string              `json:"status"`
+	Selectors     map[string]int      `json:"selectors"`
+	Pages         []spider.TracePage  `json:"pages"`
+	Candidates    []diagnoseCandidate `json:"candidates"`
+	SpiderSeconds float64             `json:"spider_seconds"`
+	CheckSeconds  float64             `json:"check_seconds"`
+}
+
+// diagnoseCandidate is the check result of one candidate URL
+type diagnoseCandidate struct {
+	URL         string `json:"url"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	PDF         bool   `json:"pdf"`
+	Error       string `json:"error,omitempty"`
+}
+
+// DoiDiagnoseCmd is the cobra command object to run bget doi diagnose
+var DoiDiagnoseCmd = &cobra.Command{
+	Use:   "diagnose [prefix1 doi2...]",
+	Short: "Audit the DOI spiders using a sample DOI per prefix.",
+	Long:  `Audit the DOI spiders using a sample DOI per prefix: matched selectors, candidate URLs, HTTP status, PDF validation and timing. More see here https://github.com/openanno/bget.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		doiDiagnoseCmdRunOptions(cmd, args)
+	},
+}
+
+func doiDiagnoseCmdRunOptions(cmd *cobra.Command, args []string) {
+	initCmd(cmd, args)
+	if (offline || saveFixtures) && fixturesDir == "" {
+		log.Fatalln("--offline and --save-fixtures require --fixtures-dir.")
+	}
+	if fixturesDir != "" && offline == saveFixtures {
+		log.Fatalln("--fixtures-dir requires one of --offline and --save-fixtures.")
+	}
+	fixtures := loadDiagnoseFixtures(args)
+	if len(fixtures) == 0 {
+		log.Fatalln("No DOI to diagnose.")
+	}
+	var ft *fixtureTransport
+	if fixturesDir != "" {
+		ft = newFixtureTransport(fixturesDir, !offline)
+		spider.SetTransport(ft)
+		defer spider.SetTransport(nil)
+	}
+	results := make([]doiDiagnosis, len(fixtures))
+	var sem = make(chan bool, bgetClis.Thread)
+	var wg sync.WaitGroup
+	for i := range fixtures {
+		wg.Add(1)
+		sem <- true
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = diagnoseDoi(fixtures[i], ft)
+		}(i)
+	}
+	wg.Wait()
+	if ft != nil && saveFixtures {
+		ft.saveIndex()
+	}
+	printDiagnoses(results)
+}
+
+// loadDiagnoseFixtures read the fixture list (--fixtures, _meta/doi/diagnose.json
+// or the remote copy), args can be prefixes to filter or extra DOIs
+func loadDiagnoseFixtures(args []string) (fixtures []diagnoseFixture) {
+	prefixes := make(map[string]bool)
+	for _, v := range args {
+		if strings.Contains(v, "/") {
+			fixtures = append(fixtures, diagnoseFixture{Prefix: strings.Split(v, "/")[0], Doi: v})
+		} else {
+			prefixes[v] = true
+		}
+	}
+	if len(fixtures) > 0 && len(prefixes) == 0 {
+		return fixtures
+	}
+	fn := diagnoseFixtures
+	if fn == "" {
+		us, _ := user.Current()
+		cacheDir := path.Join(us.HomeDir, ".config", "bget", "meta", "doi")
+		for _, v := range []string{path.Join("_meta", "doi", "diagnose.json"), path.Join(cacheDir, "diagnose.json")} {
+			if hasFile, _ := cio.PathExists(v); hasFile {
+				fn = v
+				break
+			}
+		}
+		if fn == "" {
+			netOpt := setNetParams(&bgetClis)
+			netOpt.Overwrite = true
+			cnet.HTTPGetURLs([]string{DiagnoseFixturesURL}, []string{cacheDir}, netOpt)
+			fn = path.Join(cacheDir, "diagnose.json")
+		}
+	}
+	buf, err := ioutil.ReadFile(fn)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	all := []diagnoseFixture{}
+	if err = json.Unmarshal(buf, &all); err != nil {
+		log.Fatalf("Parsing %s: %v", fn, err)
+	}
+	for _, v := range all {
+		if len(prefixes) == 0 || prefixes[v.Prefix] {
+			fixtures = append(fixtures, v)
+		}
+	}
+	return append(fixtures, poolWithoutSample(all, prefixes)...)
+}
+
+// poolWithoutSample return the prefixes of DoiSpidersPool without a sample
+// DOI in fixtures (filtered by prefixes), reported as no-sample
+func poolWithoutSample(fixtures []diagnoseFixture, prefixes map[string]bool) (missing []diagnoseFixture) {
+	hasSample := make(map[string]bool)
+	for _, v := range fixtures {
+		hasSample[v.Prefix] = true
+	}
+	for k := range spider.DoiSpidersPool {
+		if !hasSample[k] && (len(prefixes) == 0 || prefixes[k]) {
+			missing = append(missing, diagnoseFixture{Prefix: k})
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].Prefix < missing[j].Prefix
+	})
+	return missing
+}
+
+// diagnoseDoi run the pool spider and universal spider without retries,
+// and check the candidate URLs
+func diagnoseDoi(fx diagnoseFixture, ft *fixtureTransport) (ret doiDiagnosis) {
+	ret.Prefix = fx.Prefix
+	ret.Doi = fx.Doi
+	if fx.Doi == "" {
+		if fn, ok := spider.DoiSpidersPool[fx.Prefix]; ok {
+			ret.Spider = spiderName(fn)
+		}
+		ret.Status = "no-sample"
+		return ret
+	}
+	opt := newDoiSpiderOpt(fx.Doi)
+	opt.FullText = true
+	opt.Supplementary = false
+	opt.PrintSiteMeta = false
+	opt.PrintCrossRefMeta = false
+	opt.Trace = spider.NewSpiderTrace()
+	if offline {
+		if landing := ft.landing(fx.Doi); landing != "" {
+			opt.URL, _ = neturl.Parse(landing)
+		} else {
+			ret.Status = "no-fixture"
+			return ret
+		}
+	} else {
+		resolveDoiURL(opt)
+		if ft != nil && opt.URL != nil {
+			ft.register(fx.Doi, opt.URL.String())
+		}
+	}
+	if opt.URL != nil {
+		ret.URL = opt.URL.String()
+	}
+	start := time.Now()
+	var urls []string
+	var spiders []string
+	if fn, ok := spider.DoiSpidersPool[fx.Prefix]; ok {
+		spiders = append(spiders, spiderName(fn))
+		urls = fn(opt)
+	}
+	if len(urls) == 0 {
+		spiders = append(spiders, spiderName(spider.UniVersalDoiSpider))
+		urls = spider.UniVersalDoiSpider(opt)
+	}
+	ret.Spider = strings.Join(spiders, " > ")
+	ret.SpiderSeconds = time.Since(start).Seconds()
+	ret.Selectors = opt.Trace.Selectors
+	ret.Pages = opt.Trace.Pages
+	start = time.Now()
+	hasPdf := false
+	for _, v := range urls {
+		c := diagnoseCandidate{URL: v}
+		if !offline {
+			c = checkCandidate(context.Background(), v)
+		}
+		hasPdf = hasPdf || c.PDF
+		ret.Candidates = append(ret.Candidates, c)
+	}
+	ret.CheckSeconds = time.Since(start).Seconds()
+	switch {
+	case len(urls) == 0:
+		ret.Status = "no-candidates"
+	case offline:
+		ret.Status = "candidates"
+	case hasPdf:
+		ret.Status = "ok"
+	default:
+		ret.Status = "no-pdf"
+	}
+	return ret
+}
+
+func spiderName(fn func(opt *spider.DoiSpiderOpt) []string) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// checkCandidate request the first bytes of URL and validate PDF files
+func checkCandidate(ctx context.Context, link string) (c diagnoseCandidate) {
+	c.URL = link
+	client := cassette.NewHTTPClient(bgetClis.Timeout, bgetClis.Proxy)
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		c.Error = err.Error()
+		return c
+	}
+	cnet.SetDefaultReqHeader(req)
+	req.Header.Set("Range", "bytes=0-1023")
+	resp, err := client.Do(req)
+	if err != nil {
+		c.Error = err.Error()
+		return c
+	}
+	defer resp.Body.Close()
+	c.Status = resp.StatusCode
+	c.ContentType = resp.Header.Get("Content-Type")
+	buf := make([]byte, 1024)
+	n, _ := io.ReadFull(resp.Body, buf)
+	c.PDF = bytes.HasPrefix(bytes.TrimSpace(buf[0:n]), []byte("%PDF"))
+	return c
+}
+
+func printDiagnoses(results []doiDiagnosis) {
+	if bgetClis.PrintFormat == "json" {
+		buf, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(buf))
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Prefix", "DOI", "Spider", "Status", "Selectors", "Candidates", "HTTP", "Time(s)"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	ok := 0
+	for _, v := range results {
+		if v.Status == "ok" || v.Status == "candidates" {
+			ok++
+		}
+		selectors := []string{}
+		for k := range v.Selectors {
+			selectors = append(selectors, k)
+		}
+		sort.Strings(selectors)
+		status := []string{}
+		for _, c := range v.Candidates {
+			if c.Error != "" {
+				status = append(status, "error")
+			} else if c.Status != 0 {
+				status = append(status, strconv.Itoa(c.Status))
+			}
+		}
+		table.Append([]string{v.Prefix, v.Doi, v.Spider, v.Status,
+			strconv.Itoa(len(selectors)), strconv.Itoa(len(v.Candidates)),
+			strings.Join(status, ","), fmt.Sprintf("%.1f", v.SpiderSeconds+v.CheckSeconds)})
+	}
+	table.SetFooter([]string{"", "", "", fmt.Sprintf("%d/%d ok", ok, len(results)), "", "", "", ""})
+	table.Render()
+}
+
+// fixtureTransport saves the DOI landing pages into Dir (Save is true), or
+// serves the saved pages offline
+type fixtureTransport struct {
+	Dir   string
+	Save  bool
+	base  http.RoundTripper
+	index map[string]string
+	pages map[string]string
+	lock  sync.Mutex
+}
+
+func newFixtureTransport(dir string, save bool) *fixtureTransport {
+	t := &fixtureTransport{
+		Dir:   dir,
+		Save:  save && saveFixtures,
+		base:  cassette.NewHTTPClient(bgetClis.Timeout, bgetClis.Proxy).Transport,
+		index: make(map[string]string),
+		pages: make(map[string]string),
+	}
+	if t.Save {
+		cio.CreateDir(dir)
+		return t
+	}
+	buf, err := ioutil.ReadFile(path.Join(dir, "index.json"))
+	if err != nil {
+		log.Warnln(err)
+		return t
+	}
+	if err = json.Unmarshal(buf, &t.index); err != nil {
+		log.Warnln(err)
+	}
+	for doi, landing := range t.index {
+		t.register(doi, landing)
+	}
+	return t
+}
+
+func (t *fixtureTransport) fixtureFile(doi string) string {
+	return path.Join(t.Dir, sanitizeFilename(doi)+".html")
+}
+
+// register map the doi.org link and landing page of DOI to the fixture file
+func (t *fixtureTransport) register(doi string, landing string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.index[doi] = landing
+	t.pages["https://doi.org/"+doi] = t.fixtureFile(doi)
+	t.pages[landing] = t.fixtureFile(doi)
+}
+
+func (t *fixtureTransport) landing(doi string) string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.index[doi]
+}
+
+func (t *fixtureTransport) saveIndex() {
+	buf, _ := json.MarshalIndent(t.index, "", "  ")
+	if err := ioutil.WriteFile(path.Join(t.Dir, "index.json"), buf, 0664); err != nil {
+		log.Warnln(err)
+	}
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.lock.Lock()
+	fn, ok := t.pages[req.URL.String()]
+	t.lock.Unlock()
+	if !t.Save {
+		var buf []byte
+		var err error
+		if ok {
+			buf, err = ioutil.ReadFile(fn)
+		}
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Header:     http.Header{"Content-Type": []string{"text/html; charset=utf-8"}},
+			Body:       ioutil.NopCloser(bytes.NewReader(buf)),
+			Request:    req,
+		}
+		if !ok || err != nil {
+			resp.StatusCode = http.StatusNotFound
+			resp.Status = "404 Not Found"
+		}
+		return resp, nil
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || !ok {
+		return resp, err
+	}
+	// follow the redirects of landing page
+	if location, err := resp.Location(); err == nil {
+		t.lock.Lock()
+		t.pages[location.String()] = fn
+		t.lock.Unlock()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Saving fixture %s => %s", req.URL.String(), fn)
+	if err = ioutil.WriteFile(fn, buf, 0664); err != nil {
+		log.Warnln(err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(buf))
+	return resp, nil
+}
+
+func init() {
+	DoiDiagnoseCmd.Flags().StringVarP(&diagnoseFixtures, "fixtures", "", "", "fixture list of sample DOIs (default _meta/doi/diagnose.json).")
+	DoiDiagnoseCmd.Flags().StringVarP(&fixturesDir, "fixtures-dir", "", "", "dir of HTML fixtures, used with --save-fixtures or --offline.")
+	DoiDiagnoseCmd.Flags().BoolVarP(&saveFixtures, "save-fixtures", "", false, "save the landing pages into --fixtures-dir.")
+	DoiDiagnoseCmd.Flags().BoolVarP(&offline, "offline", "", false, "run against the HTML fixtures in --fixtures-dir without network.")
+	DoiDiagnoseCmd.Flags().StringVarP(&(bgetClis.PrintFormat), "format", "", "table", "output format (table, json).")
+	DoiDiagnoseCmd.Flags().StringVarP(&(bgetClis.Proxy), "proxy", "", "", "HTTP proxy to download.")
+	DoiDiagnoseCmd.Flags().IntVarP(&(bgetClis.Thread), "thread", "t", 1, "Concurrency thread.")
+	DoiDiagnoseCmd.Flags().IntVarP(&bgetClis.Timeout, "timeout", "", 35, "Set the timeout of per request.")
+	DoiDiagnoseCmd.Example = `  # spider prefixes without a sample DOI are reported as no-sample
+  bget doi diagnose -t 5
+  bget doi diagnose 10.1038 10.1016 --format json
+  bget doi diagnose 10.1073/pnas.1814397115
+  # save the landing pages and audit the selectors offline
+  bget doi diagnose --fixtures-dir fixtures --save-fixtures
+  bget doi diagnose --fixtures-dir fixtures --offline`
+	DoiCmd.AddCommand(DoiDiagnoseCmd)
+}
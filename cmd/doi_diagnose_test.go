@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/openanno/bget/spider"
+)
+
+func TestDiagnoseDoiOffline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bget-diagnose")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	index := `{"10.9999/bget.1": "https://www.example.org/article/1"}`
+	page := `<html><head>
+<meta name="citation_doi" content="10.9999/bget.1">
+<meta name="citation_pdf_url" content="https://www.example.org/article/1.pdf">
+</head><body></body></html>`
+	ioutil.WriteFile(path.Join(dir, "index.json"), []byte(index), 0664)
+	ioutil.WriteFile(path.Join(dir, sanitizeFilename("10.9999/bget.1")+".html"), []byte(page), 0664)
+
+	oldOffline, oldTimeout := offline, bgetClis.Timeout
+	defer func() { offline, bgetClis.Timeout = oldOffline, oldTimeout }()
+	offline, bgetClis.Timeout = true, 5
+	ft := newFixtureTransport(dir, false)
+	spider.SetTransport(ft)
+	defer spider.SetTransport(nil)
+
+	ret := diagnoseDoi(diagnoseFixture{Prefix: "10.9999", Doi: "10.9999/bget.1"}, ft)
+	if ret.Status != "candidates" || ret.URL != "https://www.example.org/article/1" {
+		t.Fatalf("unexpected diagnosis: %s %s", ret.Status, ret.URL)
+	}
+	if len(ret.Candidates) == 0 || ret.Candidates[0].URL != "https://www.example.org/article/1.pdf" {
+		t.Errorf("unexpected candidates: %v", ret.Candidates)
+	}
+	if ret.Selectors["meta[name]"] != 2 {
+		t.Errorf("unexpected selectors: %v", ret.Selectors)
+	}
+	if len(ret.Pages) == 0 || ret.Pages[0].Status != 200 {
+		t.Errorf("unexpected pages: %v", ret.Pages)
+	}
+	// DOIs without fixture are reported
+	if ret = diagnoseDoi(diagnoseFixture{Prefix: "10.9999", Doi: "10.9999/bget.2"}, ft); ret.Status != "no-fixture" {
+		t.Errorf("unexpected status: %s", ret.Status)
+	}
+}
+
+func TestPoolWithoutSample(t *testing.T) {
+	fixtures := []diagnoseFixture{{Prefix: "10.1016", Doi: "10.1016/j.cell.2019.10.015"}}
+	missing := poolWithoutSample(fixtures, nil)
+	if len(missing) != len(spider.DoiSpidersPool)-1 {
+		t.Errorf("unexpected number of prefixes without sample: %d", len(missing))
+	}
+	for _, v := range missing {
+		if v.Prefix == "10.1016" || v.Doi != "" {
+			t.Errorf("unexpected fixture: %+v", v)
+		}
+	}
+	missing = poolWithoutSample(fixtures, map[string]bool{"10.1016": true, "10.1094": true})
+	if len(missing) != 1 || missing[0].Prefix != "10.1094" {
+		t.Errorf("unexpected prefixes without sample: %+v", missing)
+	}
+	if ret := diagnoseDoi(missing[0], nil); ret.Status != "no-sample" || ret.Spider == "" {
+		t.Errorf("unexpected diagnosis: %+v", ret)
+	}
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/openanno/bget/api/fetch"
+	cio "github.com/openbiox/ligo/io"
+)
+
+var follow string
+var followDepth int
+var followMax int
+var graphFormat string
+
+// citationEdge is the edge of citation graph: Citing cites Cited
+type citationEdge struct {
+	Citing string
+	Cited  string
+}
+
+// followCitations walk the citation graph from seeds to followDepth, and
+// return the deduplicated DOIs (at most followMax) including the seeds
+func followCitations(seeds []string) []string {
+	if follow == "" {
+		return seeds
+	}
+	if follow != "references" && follow != "cited-by" {
+		log.Fatalf("Unsupported --follow %s (references, cited-by).", follow)
+	}
+	if graphFormat != "csv" && graphFormat != "graphml" {
+		log.Fatalf("Unsupported --graph-format %s (csv, graphml).", graphFormat)
+	}
+	nodes, depths, edges := walkCitations(seeds, citationNeighbors)
+	if len(nodes) >= followMax {
+		log.Warnf("Reaching --max %d, the citation graph is truncated.", followMax)
+	}
+	log.Infof("Citation graph: %d DOIs, %d edges.", len(nodes), len(edges))
+	writeCitationGraph(nodes, depths, edges)
+	return nodes
+}
+
+// walkCitations walk breadth-first from seeds, an edge reported more than once
+// (e.g. repeated in the citation list or in different case) is kept once
+func walkCitations(seeds []string, neighborsOf func(string) []string) (nodes []string, depths map[string]int, edges []citationEdge) {
+	names := make(map[string]string)
+	depths = make(map[string]int)
+	seen := make(map[citationEdge]bool)
+	var addEdge = func(citing, cited string) {
+		key := citationEdge{Citing: strings.ToLower(citing), Cited: strings.ToLower(cited)}
+		if seen[key] || key.Citing == key.Cited {
+			return
+		}
+		seen[key] = true
+		edges = append(edges, citationEdge{Citing: citing, Cited: cited})
+	}
+	var addNode = func(doi string, depth int) bool {
+		key := strings.ToLower(doi)
+		if _, ok := names[key]; ok || len(nodes) >= followMax {
+			return false
+		}
+		names[key] = doi
+		depths[doi] = depth
+		nodes = append(nodes, doi)
+		return true
+	}
+	frontier := []string{}
+	for _, v := range seeds {
+		if addNode(v, 0) {
+			frontier = append(frontier, v)
+		}
+	}
+	for depth := 1; depth <= followDepth && len(frontier) > 0 && len(nodes) < followMax; depth++ {
+		log.Infof("Following %s of %d DOIs (depth %d).", follow, len(frontier), depth)
+		neighbors := make([][]string, len(frontier))
+		var wg sync.WaitGroup
+		var sem = make(chan bool, bgetClis.Thread)
+		for i, doi := range frontier {
+			wg.Add(1)
+			sem <- true
+			go func(i int, doi string) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				neighbors[i] = neighborsOf(doi)
+			}(i, doi)
+		}
+		wg.Wait()
+		next := []string{}
+		for i, doi := range frontier {
+			for _, v := range neighbors[i] {
+				if addNode(v, depth) {
+					next = append(next, v)
+				}
+				name, ok := names[strings.ToLower(v)]
+				if !ok {
+					continue
+				}
+				if follow == "references" {
+					addEdge(doi, name)
+				} else {
+					addEdge(name, doi)
+				}
+			}
+		}
+		frontier = next
+	}
+	return nodes, depths, edges
+}
+
+// citationNeighbors return the references (CrossRef) or citing papers
+// (OpenCitations, fallback to OpenAlex) of doi
+func citationNeighbors(doi string) (dois []string) {
+	bapiClis := setBapiClis()
+	if follow == "references" {
+		work, err := fetch.CrossRefWork(doi, bapiClis)
+		if err != nil {
+			log.Warnf("Crossref references of %s: %v", doi, err)
+			return dois
+		}
+		noDoi := 0
+		for _, v := range work.Reference {
+			if v.DOI != "" {
+				dois = append(dois, v.DOI)
+			} else {
+				noDoi++
+			}
+		}
+		if noDoi > 0 {
+			log.Infof("%d of %d references of %s have no DOI.", noDoi, len(work.Reference), doi)
+		}
+		return dois
+	}
+	dois, err := fetch.OpenCitationsCitedBy(doi, bapiClis)
+	if err != nil {
+		log.Warnf("OpenCitations citations of %s: %v", doi, err)
+	}
+	if len(dois) == 0 {
+		if dois, err = fetch.OpenAlexCitedBy(doi, followMax, bapiClis); err != nil {
+			log.Warnf("OpenAlex citations of %s: %v", doi, err)
+		}
+	}
+	return dois
+}
+
+func writeCitationGraph(nodes []string, depths map[string]int, edges []citationEdge) {
+	outfn := path.Join(bgetClis.DownloadDir, "citation.graph."+graphFormat)
+	var buf bytes.Buffer
+	if graphFormat == "graphml" {
+		var escape = func(s string) string {
+			var b bytes.Buffer
+			xml.EscapeText(&b, []byte(s))
+			return b.String()
+		}
+		buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+		buf.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+		buf.WriteString(`  <key id="depth" for="node" attr.name="depth" attr.type="int"/>` + "\n")
+		buf.WriteString(`  <graph id="citations" edgedefault="directed">` + "\n")
+		for _, v := range nodes {
+			buf.WriteString(fmt.Sprintf(`    <node id="%s"><data key="depth">%d</data></node>`+"\n", escape(v), depths[v]))
+		}
+		for _, v := range edges {
+			buf.WriteString(fmt.Sprintf(`    <edge source="%s" target="%s"/>`+"\n", escape(v.Citing), escape(v.Cited)))
+		}
+		buf.WriteString("  </graph>\n</graphml>\n")
+	} else {
+		w := csv.NewWriter(&buf)
+		w.Write([]string{"citing", "cited"})
+		for _, v := range edges {
+			w.Write([]string{v.Citing, v.Cited})
+		}
+		w.Flush()
+	}
+	log.Infof("Saving citation graph => %s", outfn)
+	cio.CreateFileParDir(outfn)
+	if err := ioutil.WriteFile(outfn, buf.Bytes(), 0664); err != nil {
+		log.Warnln(err)
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWalkCitations(t *testing.T) {
+	graph := map[string][]string{
+		"10.1/a": {"10.1/b", "10.1/c", "10.1/B", "10.1/a"},
+		"10.1/b": {"10.1/c", "10.1/d"},
+		"10.1/c": {"10.1/a"},
+		"10.1/d": {"10.1/e"},
+	}
+	neighborsOf := func(doi string) []string { return graph[strings.ToLower(doi)] }
+	oldFollow, oldDepth, oldMax, oldThread := follow, followDepth, followMax, bgetClis.Thread
+	defer func() { follow, followDepth, followMax, bgetClis.Thread = oldFollow, oldDepth, oldMax, oldThread }()
+	follow, bgetClis.Thread = "references", 2
+	for _, c := range []struct {
+		depth, max int
+		nodes      string
+		edges      string
+	}{
+		{0, 100, "10.1/a", ""},
+		{1, 100, "10.1/a 10.1/b 10.1/c", "10.1/a>10.1/b 10.1/a>10.1/c"},
+		{2, 100, "10.1/a 10.1/b 10.1/c 10.1/d", "10.1/a>10.1/b 10.1/a>10.1/c 10.1/b>10.1/c 10.1/b>10.1/d 10.1/c>10.1/a"},
+		{2, 2, "10.1/a 10.1/b", "10.1/a>10.1/b"},
+	} {
+		followDepth, followMax = c.depth, c.max
+		nodes, depths, edges := walkCitations([]string{"10.1/a", "10.1/A"}, neighborsOf)
+		got := []string{}
+		for _, v := range edges {
+			got = append(got, v.Citing+">"+v.Cited)
+		}
+		if strings.Join(nodes, " ") != c.nodes || strings.Join(got, " ") != c.edges {
+			t.Errorf("depth %d, max %d: unexpected graph %v %v", c.depth, c.max, nodes, got)
+		}
+		if c.depth == 2 && c.max == 100 && depths["10.1/d"] != 2 {
+			t.Errorf("unexpected depth of 10.1/d: %d", depths["10.1/d"])
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openanno/bget/api/fetch"
+	"github.com/openbiox/ligo/stringo"
+)
+
+// ArxivDoiPrefix is the DataCite DOI prefix of arXiv
+const ArxivDoiPrefix = "10.48550/arXiv."
+
+// doiIDType detect the type of identifier: doi, pmid, pmcid, arxiv, handle,
+// ark, purl, curie or unknown
+func doiIDType(id string) (idType string, value string) {
+	id = strings.TrimSpace(id)
+	lower := strings.ToLower(id)
+	switch {
+	case strings.HasPrefix(lower, "pmid:"):
+		return "pmid", strings.TrimSpace(id[len("pmid:"):])
+	case stringo.StrDetect(lower, "^pmc[0-9]+$"):
+		return "pmcid", strings.ToUpper(id)
+	case strings.HasPrefix(lower, "pmcid:"):
+		return "pmcid", strings.ToUpper(strings.TrimSpace(id[len("pmcid:"):]))
+	case strings.HasPrefix(lower, "arxiv:"):
+		return "arxiv", strings.TrimSpace(id[len("arxiv:"):])
+	case strings.HasPrefix(lower, "doi:"):
+		return "doi", strings.TrimSpace(id[len("doi:"):])
+	case stringo.StrDetect(lower, "^https?://(dx[.])?doi[.]org/"):
+		return "doi", stringo.StrReplaceAll(id, "^https?://(dx[.])?doi[.]org/", "")
+	case stringo.StrDetect(id, "^10[.][0-9]+/"):
+		return "doi", id
+	}
+	if scheme, value := parsePID(id); scheme != "" {
+		return scheme, value
+	}
+	return "unknown", id
+}
+
+// convertDoiIDs convert PMID, PMCID and arXiv ID to DOI, idMap maps the input ID to DOI
+func convertDoiIDs(ids []string) (dois []string, idMap map[string]string, unconvertible []string) {
+	idMap = make(map[string]string)
+	ncbiIDs := []string{}
+	arxivIDs := []string{}
+	rawIDs := make(map[string]string)
+	for _, v := range ids {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		idType, value := doiIDType(v)
+		switch idType {
+		case "doi", "handle", "ark", "purl", "curie":
+			dois = append(dois, value)
+			idMap[v] = value
+		case "pmid", "pmcid":
+			ncbiIDs = append(ncbiIDs, value)
+			rawIDs[strings.ToUpper(value)] = v
+		case "arxiv":
+			arxivIDs = append(arxivIDs, value)
+			rawIDs[strings.ToLower(fetch.ArxivID(value))] = v
+		default:
+			// keep the legacy behaviour for identifiers like 'xxx/yyy'
+			if strings.Contains(value, "/") {
+				dois = append(dois, value)
+				idMap[v] = value
+			} else {
+				unconvertible = append(unconvertible, v)
+			}
+		}
+	}
+	bapiClis := setBapiClis()
+	if len(ncbiIDs) > 0 {
+		converted := make(map[string]bool)
+		records, err := fetch.NcbiIDConv(ncbiIDs, bapiClis)
+		if err != nil {
+			log.Warnln(err)
+		}
+		for _, r := range records {
+			if r.Doi != "" {
+				log.Infof("Converting %s => %s", r.RequestedID, r.Doi)
+				dois = append(dois, string(r.Doi))
+				converted[strings.ToUpper(string(r.RequestedID))] = true
+				idMap[rawIDs[strings.ToUpper(string(r.RequestedID))]] = string(r.Doi)
+			}
+		}
+		for _, v := range ncbiIDs {
+			if !converted[strings.ToUpper(v)] {
+				unconvertible = append(unconvertible, v)
+			}
+		}
+	}
+	if len(arxivIDs) > 0 {
+		converted := make(map[string]bool)
+		entries, err := fetch.Arxiv(arxivIDs, bapiClis)
+		if err != nil {
+			log.Warnln(err)
+		}
+		for _, v := range entries {
+			id := fetch.ArxivID(v.ID)
+			if v.Doi != "" {
+				log.Infof("arXiv:%s is published as %s", id, v.Doi)
+			}
+			raw := rawIDs[strings.ToLower(id)]
+			doi := ArxivDoiPrefix + id
+			// keep the requested version, e.g. arXiv:1706.03762v5
+			if _, version := splitPreprintVersion(raw); version > 0 {
+				doi = fmt.Sprintf("%sv%d", doi, version)
+			}
+			log.Infof("Converting %s => %s", raw, doi)
+			dois = append(dois, doi)
+			converted[strings.ToLower(id)] = true
+			idMap[raw] = doi
+		}
+		for _, v := range arxivIDs {
+			if !converted[strings.ToLower(fetch.ArxivID(v))] {
+				unconvertible = append(unconvertible, "arXiv:"+v)
+			}
+		}
+	}
+	if len(unconvertible) > 0 {
+		log.Warnf("Unconvertible IDs (%d): %s", len(unconvertible), strings.Join(unconvertible, ", "))
+	}
+	return dois, idMap, unconvertible
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import "testing"
+
+func TestDoiIDType(t *testing.T) {
+	for id, want := range map[string][2]string{
+		"10.1038/s41586-019-1844-5":                   {"doi", "10.1038/s41586-019-1844-5"},
+		" doi:10.1038/s41586-019-1844-5 ":             {"doi", "10.1038/s41586-019-1844-5"},
+		"https://doi.org/10.1038/s41586-019-1844-5":   {"doi", "10.1038/s41586-019-1844-5"},
+		"http://dx.doi.org/10.1038/s41586-019-1844-5": {"doi", "10.1038/s41586-019-1844-5"},
+		"PMID:31836890":                               {"pmid", "31836890"},
+		"pmc6927479":                                  {"pmcid", "PMC6927479"},
+		"PMCID: pmc6927479":                           {"pmcid", "PMC6927479"},
+		"arXiv:1706.03762v5":                          {"arxiv", "1706.03762v5"},
+		"ark:/13030/tf5p30086k":                       {"ark", "ark:/13030/tf5p30086k"},
+		"31836890":                                    {"unknown", "31836890"},
+	} {
+		if idType, value := doiIDType(id); idType != want[0] || value != want[1] {
+			t.Errorf("unexpected type of %q: %s %s", id, idType, value)
+		}
+	}
+}
+
+func TestConvertDoiIDsLocal(t *testing.T) {
+	// only identifiers that need no remote conversion
+	dois, idMap, unconvertible := convertDoiIDs([]string{
+		"doi:10.1038/s41586-019-1844-5", "", "hdl:10013/epic.51096", "abc/def", "31836890",
+	})
+	if len(dois) != 3 || dois[0] != "10.1038/s41586-019-1844-5" || dois[1] != "hdl:10013/epic.51096" || dois[2] != "abc/def" {
+		t.Errorf("unexpected DOIs: %v", dois)
+	}
+	if idMap["doi:10.1038/s41586-019-1844-5"] != "10.1038/s41586-019-1844-5" || len(idMap) != 3 {
+		t.Errorf("unexpected ID map: %v", idMap)
+	}
+	if len(unconvertible) != 1 || unconvertible[0] != "31836890" {
+		t.Errorf("unexpected unconvertible IDs: %v", unconvertible)
+	}
+}
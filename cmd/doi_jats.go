@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/openanno/bget/api/fetch"
+	cio "github.com/openbiox/ligo/io"
+)
+
+var doiFormat string
+var jatsConvert string
+
+// jatsNode is a node of JATS XML, Text is set for character data
+type jatsNode struct {
+	Name     string
+	Attr     map[string]string
+	Children []*jatsNode
+	Text     string
+}
+
+// doiFormats return the formats of --format, e.g. pdf,jats
+func doiFormats() map[string]bool {
+	formats := make(map[string]bool)
+	for _, v := range strings.Split(doiFormat, ",") {
+		v = strings.ToLower(strings.TrimSpace(v))
+		switch v {
+		case "pdf", "jats":
+			formats[v] = true
+		case "":
+		default:
+			log.Fatalf("Unsupported format %s (pdf, jats).", v)
+		}
+	}
+	if jatsConvert != "" && jatsConvert != "text" && jatsConvert != "markdown" {
+		log.Fatalf("Unsupported JATS conversion %s (text, markdown).", jatsConvert)
+	}
+	return formats
+}
+
+// fetchJats save the JATS XML of DOI from Europe PMC (or PMC), pmcid is
+// queried via NCBI ID converter if empty
+func (task *doiTask) fetchJats(pmcid string) {
+	bapiClis := setBapiClis()
+	if pmcid == "" {
+		records, err := fetch.NcbiIDConv([]string{task.Doi}, bapiClis)
+		if err != nil {
+			log.Warnln(err)
+		}
+		for _, r := range records {
+			pmcid = string(r.Pmcid)
+		}
+	}
+	if pmcid == "" {
+		log.Warnf("%s is not available in PMC, skipping JATS XML.", task.Doi)
+		return
+	}
+	buf, err := fetch.EuropePmcFullTextXML(pmcid, bapiClis)
+	if err != nil {
+		log.Warnf("%v, trying PMC.", err)
+		if buf, err = fetch.PmcFullTextXML(pmcid, bapiClis); err != nil {
+			log.Warnln(err)
+			return
+		}
+	}
+	outfn := task.metaFile(pmcid + ".xml")
+	cio.CreateFileParDir(outfn)
+	if err = ioutil.WriteFile(outfn, buf, 0664); err != nil {
+		log.Warnln(err)
+		return
+	}
+	log.Infof("Saving JATS XML of %s => %s", task.Doi, outfn)
+	task.Files = append(task.Files, outfn)
+	if jatsConvert == "" {
+		return
+	}
+	text, err := renderJats(buf, jatsConvert == "markdown")
+	if err != nil {
+		log.Warnln(err)
+		return
+	}
+	ext := ".txt"
+	if jatsConvert == "markdown" {
+		ext = ".md"
+	}
+	outfn = strings.TrimSuffix(outfn, ".xml") + ext
+	if err = ioutil.WriteFile(outfn, []byte(text), 0664); err != nil {
+		log.Warnln(err)
+		return
+	}
+	task.Files = append(task.Files, outfn)
+}
+
+// parseJats parse JATS XML into a node tree
+func parseJats(buf []byte) (*jatsNode, error) {
+	d := xml.NewDecoder(bytes.NewReader(buf))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+	root := &jatsNode{Name: "#root"}
+	stack := []*jatsNode{root}
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return root, err
+		}
+		parent := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &jatsNode{Name: t.Name.Local, Attr: make(map[string]string)}
+			for _, a := range t.Attr {
+				node.Attr[a.Name.Local] = a.Value
+			}
+			parent.Children = append(parent.Children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[0 : len(stack)-1]
+			}
+		case xml.CharData:
+			parent.Children = append(parent.Children, &jatsNode{Text: string(t)})
+		}
+	}
+	return root, nil
+}
+
+// find return the first descendant named by the path, e.g. front/article-meta
+func (node *jatsNode) find(names ...string) *jatsNode {
+	if node == nil || len(names) == 0 {
+		return node
+	}
+	for _, c := range node.Children {
+		if c.Name == names[0] {
+			if ret := c.find(names[1:]...); ret != nil {
+				return ret
+			}
+		}
+	}
+	for _, c := range node.Children {
+		if c.Name != "" && c.Name != names[0] {
+			if ret := c.find(names...); ret != nil {
+				return ret
+			}
+		}
+	}
+	return nil
+}
+
+// child return the first child named name
+func (node *jatsNode) child(name string) *jatsNode {
+	if node == nil {
+		return nil
+	}
+	for _, c := range node.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// jatsInline is the inline elements of JATS, e.g. 10<sup>5</sup> (10^5), the
+// others are separated by space in text
+var jatsInline = map[string]bool{"italic": true, "bold": true, "sup": true, "sub": true, "sc": true,
+	"underline": true, "overline": true, "strike": true, "monospace": true, "roman": true, "sans-serif": true,
+	"xref": true, "ext-link": true, "uri": true, "email": true, "abbrev": true, "named-content": true,
+	"styled-content": true, "inline-formula": true, "inline-graphic": true, "tex-math": true}
+
+// text return the collapsed text of node
+func (node *jatsNode) text() string {
+	if node == nil {
+		return ""
+	}
+	var buf strings.Builder
+	var walk func(n *jatsNode)
+	walk = func(n *jatsNode) {
+		if n.Name == "" {
+			buf.WriteString(n.Text)
+			return
+		}
+		// keep exponents and formulas readable, e.g. 10^5 and H_2O
+		switch n.Name {
+		case "sup":
+			buf.WriteString("^")
+		case "sub":
+			buf.WriteString("_")
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+		// keep the words of adjacent block elements apart
+		if !jatsInline[n.Name] {
+			buf.WriteString(" ")
+		}
+	}
+	for _, c := range node.Children {
+		walk(c)
+	}
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+// renderJats convert JATS XML to plain text or Markdown keeping the
+// section headings, figure and table captions and references
+func renderJats(buf []byte, markdown bool) (string, error) {
+	root, err := parseJats(buf)
+	if err != nil && len(root.Children) == 0 {
+		return "", err
+	}
+	article := root.find("article")
+	if article == nil {
+		return "", fmt.Errorf("no article element in JATS XML")
+	}
+	var out []string
+	heading := func(level int, title string) {
+		if title == "" {
+			return
+		}
+		if markdown {
+			out = append(out, strings.Repeat("#", level)+" "+title)
+		} else {
+			out = append(out, strings.ToUpper(title))
+		}
+	}
+	heading(1, article.find("front", "article-meta", "title-group", "article-title").text())
+	if abstract := article.find("front", "article-meta", "abstract"); abstract != nil {
+		heading(2, "Abstract")
+		renderJatsBlocks(abstract, 3, markdown, &out, heading)
+	}
+	if body := article.find("body"); body != nil {
+		renderJatsBlocks(body, 2, markdown, &out, heading)
+	}
+	if refs := article.find("back", "ref-list"); refs != nil {
+		heading(2, "References")
+		i := 0
+		for _, ref := range refs.Children {
+			if ref.Name != "ref" {
+				continue
+			}
+			i++
+			label := ref.child("label").text()
+			if label == "" {
+				label = fmt.Sprint(i)
+			}
+			text := ref.text()
+			text = strings.TrimSpace(strings.TrimPrefix(text, label))
+			out = append(out, fmt.Sprintf("%d. %s", i, text))
+		}
+	}
+	return strings.Join(out, "\n\n") + "\n", nil
+}
+
+func renderJatsBlocks(node *jatsNode, level int, markdown bool, out *[]string, heading func(int, string)) {
+	for _, c := range node.Children {
+		switch c.Name {
+		case "title":
+			// section titles are rendered by the parent sec
+		case "sec":
+			heading(level, strings.TrimSpace(c.child("label").text()+" "+c.child("title").text()))
+			renderJatsBlocks(c, level+1, markdown, out, heading)
+		case "p":
+			if t := c.text(); t != "" {
+				*out = append(*out, t)
+			}
+		case "fig", "table-wrap":
+			label := c.child("label").text()
+			caption := c.child("caption").text()
+			if label == "" && caption == "" {
+				continue
+			}
+			if markdown {
+				*out = append(*out, fmt.Sprintf("**%s** %s", label, caption))
+			} else {
+				*out = append(*out, strings.TrimSpace(label+" "+caption))
+			}
+		case "list":
+			for _, item := range c.Children {
+				if item.Name == "list-item" {
+					*out = append(*out, "- "+item.text())
+				}
+			}
+		case "label":
+		default:
+			if c.Name != "" {
+				renderJatsBlocks(c, level, markdown, out, heading)
+			}
+		}
+	}
+}
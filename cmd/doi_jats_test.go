@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderJats(t *testing.T) {
+	jats := `<?xml version="1.0"?>
+<article><front><article-meta>
+  <title-group><article-title>Galectins control <italic>MTOR</italic></article-title></title-group>
+  <abstract><p>We show that galectins&#x00A0;regulate AMPK.</p></abstract>
+</article-meta></front>
+<body>
+  <sec><title>Introduction</title><p>Autophagy is <xref ref-type="bibr" rid="r1">[1]</xref> important.</p>
+    <sec><title>Background</title><p>Lysosomes of 10<sup>5</sup> cells in H<sub>2</sub>O were <italic>in vitro</italic>-treated.</p></sec>
+    <fig id="f1"><label>Figure 1.</label><caption><title>Overview.</title><p>Model of signalling.</p></caption></fig>
+  </sec>
+</body>
+<back><ref-list><ref id="r1"><label>1</label><mixed-citation>Deretic V. Autophagy. <source>Cell</source> 2019.</mixed-citation></ref></ref-list></back>
+</article>`
+	md, err := renderJats([]byte(jats), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"# Galectins control MTOR", "## Abstract", "## Introduction", "### Background",
+		"Lysosomes of 10^5 cells in H_2O were in vitro-treated.",
+		"Autophagy is [1] important.", "**Figure 1.** Overview. Model of signalling.", "## References", "1. Deretic V. Autophagy. Cell 2019."} {
+		if !strings.Contains(md, v) {
+			t.Errorf("%q not found in:\n%s", v, md)
+		}
+	}
+}
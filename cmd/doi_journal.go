@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/openanno/bget/api/fetch"
+	"github.com/openanno/bget/api/types"
+)
+
+var journalISSN string
+var journalFrom string
+var journalUntil string
+var journalIssue string
+var journalTypes string
+
+var journalDateRe = regexp.MustCompile(`^\d{4}(-\d{2}(-\d{2})?)?$`)
+
+// journalIssueT is the volume and issue of --issue, Latest for --issue latest
+type journalIssueT struct {
+	Volume string
+	Issue  string
+	Latest bool
+}
+
+// parseJournalIssue parse vol/issue or latest
+func parseJournalIssue(s string) (ji journalIssueT, err error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "latest") {
+		return journalIssueT{Latest: true}, nil
+	}
+	items := strings.Split(s, "/")
+	if len(items) != 2 || strings.TrimSpace(items[0]) == "" || strings.TrimSpace(items[1]) == "" {
+		return ji, fmt.Errorf("invalid issue %s (vol/issue or latest)", s)
+	}
+	return journalIssueT{Volume: strings.TrimSpace(items[0]), Issue: strings.TrimSpace(items[1])}, nil
+}
+
+func (ji journalIssueT) match(work types.CrossRefWork) bool {
+	return work.Volume == ji.Volume && work.Issue == ji.Issue
+}
+
+// older indicates all works have a lower volume than the issue, works are
+// sorted by the published date so the later pages can be skipped
+func (ji journalIssueT) older(works []types.CrossRefWork) bool {
+	vol, err := strconv.Atoi(ji.Volume)
+	if err != nil || len(works) == 0 {
+		return false
+	}
+	for _, w := range works {
+		if v, err := strconv.Atoi(w.Volume); err != nil || v >= vol {
+			return false
+		}
+	}
+	return true
+}
+
+// journalFilter return the CrossRef filter of --from, --until and --work-types
+func journalFilter() (string, error) {
+	filters := []string{}
+	for i, v := range []string{journalFrom, journalUntil} {
+		if v == "" {
+			continue
+		}
+		if !journalDateRe.MatchString(v) {
+			return "", fmt.Errorf("invalid date %s (YYYY, YYYY-MM or YYYY-MM-DD)", v)
+		}
+		filters = append(filters, []string{"from-pub-date:", "until-pub-date:"}[i]+v)
+	}
+	for _, v := range strings.Split(journalTypes, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			filters = append(filters, "type:"+v)
+		}
+	}
+	return strings.Join(filters, ","), nil
+}
+
+// isResearchWork exclude the corrections and retractions typed as journal-article
+func isResearchWork(work types.CrossRefWork) bool {
+	return len(work.UpdateTo) == 0
+}
+
+// harvestJournal enumerate the DOIs of --journal
+func harvestJournal(max int) (dois []string) {
+	filter, err := journalFilter()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	bapiClis := setBapiClis()
+	var ji journalIssueT
+	sort := ""
+	var stop func(items []types.CrossRefWork) bool
+	if journalIssue != "" {
+		if ji, err = parseJournalIssue(journalIssue); err != nil {
+			log.Fatalln(err)
+		}
+		sort = "published"
+		found := false
+		stop = func(items []types.CrossRefWork) bool {
+			matched := false
+			for _, w := range items {
+				if ji.Latest && ji.Volume == "" && w.Volume != "" && w.Issue != "" {
+					ji.Volume, ji.Issue, ji.Latest = w.Volume, w.Issue, false
+					log.Infof("Latest issue of %s: %s/%s.", journalISSN, ji.Volume, ji.Issue)
+				}
+				matched = matched || ji.match(w)
+			}
+			found = found || matched
+			return (found && !matched) || ji.older(items)
+		}
+	}
+	limit := max
+	if journalIssue != "" {
+		// the issue is filtered after paging
+		limit = 0
+	}
+	works, err := fetch.CrossRefJournalWorks(journalISSN, filter, sort, limit, bapiClis, stop)
+	if err != nil {
+		log.Warnln(err)
+	}
+	toc := []string{strings.Join([]string{"doi", "type", "volume", "issue", "page", "date", "title"}, "\t")}
+	for _, w := range works {
+		if w.DOI == "" || !isResearchWork(w) || (journalIssue != "" && !ji.match(w)) {
+			continue
+		}
+		dois = append(dois, w.DOI)
+		toc = append(toc, strings.Join([]string{w.DOI, w.Type, w.Volume, w.Issue, w.Page, w.Issued.Date(),
+			strings.Join(w.Title, " ")}, "\t"))
+		if max > 0 && len(dois) >= max {
+			break
+		}
+	}
+	log.Infof("Finding %d works of journal %s.", len(dois), journalISSN)
+	outfn := path.Join(bgetClis.DownloadDir, "journal.toc.tsv")
+	if err = ioutil.WriteFile(outfn, []byte(strings.Join(toc, "\n")+"\n"), 0664); err != nil {
+		log.Warnln(err)
+	}
+	return dois
+}
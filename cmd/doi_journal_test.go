@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/openanno/bget/api/types"
+)
+
+func TestParseJournalIssue(t *testing.T) {
+	if ji, err := parseJournalIssue("52/3"); err != nil || ji.Volume != "52" || ji.Issue != "3" {
+		t.Errorf("unexpected issue: %+v %v", ji, err)
+	}
+	if ji, err := parseJournalIssue("Latest"); err != nil || !ji.Latest {
+		t.Errorf("unexpected issue: %+v %v", ji, err)
+	}
+	for _, v := range []string{"52", "52/", "/3", "52/3/1"} {
+		if _, err := parseJournalIssue(v); err == nil {
+			t.Errorf("%s should be invalid", v)
+		}
+	}
+	ji := journalIssueT{Volume: "52", Issue: "3"}
+	if ji.older([]types.CrossRefWork{{Volume: "52"}, {Volume: "51"}}) || !ji.older([]types.CrossRefWork{{Volume: "51"}, {Volume: "50"}}) {
+		t.Error("unexpected result of older")
+	}
+}
+
+func TestJournalFilter(t *testing.T) {
+	journalFrom, journalUntil, journalTypes = "2020-01", "2020-03", "journal-article"
+	defer func() {
+		journalFrom, journalUntil, journalTypes = "", "", ""
+	}()
+	if filter, err := journalFilter(); err != nil || filter != "from-pub-date:2020-01,until-pub-date:2020-03,type:journal-article" {
+		t.Errorf("unexpected filter: %s %v", filter, err)
+	}
+	journalUntil = "03/2020"
+	if _, err := journalFilter(); err == nil {
+		t.Error("invalid date should fail")
+	}
+}
@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/openanno/bget/api/types"
+	"github.com/openanno/bget/spider"
+	cio "github.com/openbiox/ligo/io"
+	cnet "github.com/openbiox/ligo/net"
+	"github.com/openbiox/ligo/stringo"
+)
+
+var nameTemplate string
+var layout string
+
+var doiLayouts = []string{"flat", "by-year", "by-journal", "by-doi"}
+
+// doiTask is the download task of one DOI
+type doiTask struct {
+	Doi       string
+	URLs      []string
+	SupplURLs []string
+	OutDir    string
+	StageDir  string
+	Prefix    string
+	Meta      doiNameMeta
+	// Candidates is the ranked candidate URLs
+	Candidates []spider.DoiCandidate
+	// Status is retracted, expression-of-concern or corrected if flagged
+	Status  string
+	Notices []doiNotice
+	// Access is the open-access status and license
+	Access *doiAccess
+	// Dataset is the record of dataset DOIs listed via repository API
+	Dataset *types.DatasetRecord
+	// Accessions is the data and code accessions cited by the paper
+	Accessions []doiAccession
+	// Files is the final path of downloaded files
+	Files []string
+	// Failed is the path of files failed the checksum, kept as .corrupt
+	Failed []string
+	// SupplIndex is the last index of the _S%d names given by --name-template
+	SupplIndex int
+}
+
+// doiNameMeta is the metadata used to render file names and layout dirs
+type doiNameMeta struct {
+	Doi           string
+	FirstAuthor   string
+	Year          string
+	Journal       string
+	JournalAbbrev string
+	Title         string
+}
+
+var reservedNames = make(map[string]bool)
+var reservedNamesLock sync.Mutex
+
+func newDoiNameMeta(doi string, opt *spider.DoiSpiderOpt, work *types.CrossRefWork) (meta doiNameMeta) {
+	meta.Doi = doi
+	if work != nil {
+		for _, v := range work.Author {
+			if v.Sequence == "first" || meta.FirstAuthor == "" {
+				meta.FirstAuthor = v.Family
+				if meta.FirstAuthor == "" {
+					meta.FirstAuthor = v.Name
+				}
+			}
+			if v.Sequence == "first" {
+				break
+			}
+		}
+		for _, d := range []types.CrossRefDateParts{work.Issued, work.PublishedPrint, work.PublishedOnline} {
+			if d.Year() != 0 {
+				meta.Year = strconv.Itoa(d.Year())
+				break
+			}
+		}
+		if len(work.ContainerTitle) > 0 {
+			meta.Journal = work.ContainerTitle[0]
+		}
+		if len(work.ShortContainerTitle) > 0 {
+			meta.JournalAbbrev = work.ShortContainerTitle[0]
+		}
+		if len(work.Title) > 0 {
+			meta.Title = work.Title[0]
+		}
+	}
+	if opt != nil && opt.CitationMeta != nil {
+		citationMeta := *opt.CitationMeta
+		if meta.FirstAuthor == "" && citationMeta["citation_author"] != "" {
+			author := strings.Split(citationMeta["citation_author"], "; ")[0]
+			if strings.Contains(author, ",") {
+				meta.FirstAuthor = strings.Split(author, ",")[0]
+			} else {
+				fields := strings.Fields(author)
+				if len(fields) > 0 {
+					meta.FirstAuthor = fields[len(fields)-1]
+				}
+			}
+		}
+		for _, k := range []string{"citation_publication_date", "citation_date", "citation_online_date"} {
+			if meta.Year == "" && citationMeta[k] != "" {
+				year := stringo.StrExtract(citationMeta[k], "[0-9]{4}", 1)
+				if len(year) > 0 {
+					meta.Year = year[0]
+				}
+			}
+		}
+		if meta.Journal == "" {
+			meta.Journal = strings.Split(citationMeta["citation_journal_title"], "; ")[0]
+		}
+		if meta.JournalAbbrev == "" {
+			meta.JournalAbbrev = strings.Split(citationMeta["citation_journal_abbrev"], "; ")[0]
+		}
+		if meta.Title == "" {
+			meta.Title = strings.Split(citationMeta["citation_title"], "; ")[0]
+		}
+	}
+	if meta.JournalAbbrev == "" {
+		meta.JournalAbbrev = meta.Journal
+	}
+	return meta
+}
+
+// shortTitle keep the first n words of title
+func shortTitle(title string, n int) string {
+	words := strings.Fields(stringo.StrReplaceAll(title, "<[^>]*>", " "))
+	if len(words) > n {
+		words = words[0:n]
+	}
+	return strings.Join(words, "-")
+}
+
+// sanitizeFilename keep the filename portable across filesystems
+func sanitizeFilename(name string) string {
+	name = stringo.StrReplaceAll(name, `[^\p{L}\p{N}._-]+`, "_")
+	name = stringo.StrReplaceAll(name, `_{2,}`, "_")
+	name = strings.Trim(name, "._-")
+	if r := []rune(name); len(r) > 150 {
+		name = string(r[0:150])
+	}
+	return name
+}
+
+func renderNameTemplate(tmpl string, meta doiNameMeta) string {
+	var unknown = func(s string) string {
+		if s == "" {
+			return "NA"
+		}
+		return sanitizeFilename(s)
+	}
+	r := strings.NewReplacer(
+		"{doi}", unknown(meta.Doi),
+		"{first_author}", unknown(meta.FirstAuthor),
+		"{year}", unknown(meta.Year),
+		"{journal}", unknown(meta.Journal),
+		"{journal_abbrev}", unknown(meta.JournalAbbrev),
+		"{title}", unknown(meta.Title),
+		"{short_title}", unknown(shortTitle(meta.Title, 6)),
+	)
+	return r.Replace(tmpl)
+}
+
+func layoutDir(meta doiNameMeta) string {
+	switch layout {
+	case "flat":
+		return bgetClis.DownloadDir
+	case "by-year":
+		if meta.Year == "" {
+			return path.Join(bgetClis.DownloadDir, "unknown-year")
+		}
+		return path.Join(bgetClis.DownloadDir, meta.Year)
+	case "by-journal":
+		if meta.JournalAbbrev == "" {
+			return path.Join(bgetClis.DownloadDir, "unknown-journal")
+		}
+		return path.Join(bgetClis.DownloadDir, sanitizeFilename(meta.JournalAbbrev))
+	}
+	return path.Join(bgetClis.DownloadDir, meta.Doi)
+}
+
+// renameRequired indicates whether files need to be downloaded into
+// a staging dir and moved to their final names
+func renameRequired() bool {
+	return nameTemplate != "" || (layout != "" && layout != "by-doi")
+}
+
+func checkDoiLayout() {
+	if layout == "" {
+		layout = "by-doi"
+	}
+	for _, v := range doiLayouts {
+		if v == layout {
+			return
+		}
+	}
+	log.Fatalf("Unsupported layout %s (%s).", layout, strings.Join(doiLayouts, ", "))
+}
+
+func newDoiTask(doi string, urls []string, opt *spider.DoiSpiderOpt, work *types.CrossRefWork) *doiTask {
+	task := &doiTask{
+		Doi:  doi,
+		URLs: urls,
+		Meta: newDoiNameMeta(doi, opt, work),
+	}
+	task.OutDir = layoutDir(task.Meta)
+	if !renameRequired() {
+		return task
+	}
+	task.StageDir = path.Join(task.OutDir, ".bget", sanitizeFilename(doi))
+	if nameTemplate != "" {
+		task.Prefix = strings.TrimSuffix(renderNameTemplate(nameTemplate, task.Meta),
+			path.Ext(nameTemplate))
+	} else if layout != "by-doi" {
+		task.Prefix = sanitizeFilename(doi)
+	}
+	return task
+}
+
+func (task *doiTask) destDir() string {
+	if task.StageDir != "" {
+		return task.StageDir
+	}
+	return task.OutDir
+}
+
+func (task *doiTask) metaFile(name string) string {
+	if task.Prefix == "" {
+		return path.Join(task.OutDir, name)
+	}
+	return path.Join(task.OutDir, task.Prefix+"."+name)
+}
+
+// finalize move the downloaded files from staging dir to the final names
+func (task *doiTask) finalize() {
+	if task.StageDir == "" {
+		for _, url := range task.URLs {
+			fn := path.Join(task.OutDir, cnet.FormatURLfileName(url, bgetClis.RemoteName, bgetClis.Timeout, bgetClis.Proxy))
+			hasFile, _ := cio.PathExists(fn)
+			hasSt, _ := cio.PathExists(fn + ".st")
+			if hasFile && !hasSt {
+				task.Files = append(task.Files, fn)
+			}
+		}
+		return
+	}
+	supplIdx := 0
+	hasMain := false
+	owned := task.previousFiles()
+	for _, url := range task.URLs {
+		fname := cnet.FormatURLfileName(url, bgetClis.RemoteName, bgetClis.Timeout, bgetClis.Proxy)
+		src := path.Join(task.StageDir, fname)
+		if hasFile, _ := cio.PathExists(src); !hasFile {
+			continue
+		}
+		if hasSt, _ := cio.PathExists(src + ".st"); hasSt {
+			continue
+		}
+		ext := fileExt(src)
+		base := strings.TrimSuffix(fname, path.Ext(fname))
+		if nameTemplate != "" {
+			base = task.Prefix
+		}
+		if hasMain || (ext != ".pdf" && nameTemplate != "") {
+			supplIdx++
+			if nameTemplate != "" {
+				base = fmt.Sprintf("%s_S%d", base, supplIdx)
+			}
+		} else {
+			hasMain = true
+		}
+		dest, existed := reserveFilename(path.Join(task.OutDir, sanitizeFilename(base)+ext), owned)
+		if existed {
+			log.Infof("%s existed.", dest)
+			task.Files = append(task.Files, dest)
+			continue
+		}
+		if err := os.Rename(src, dest); err != nil {
+			log.Warnln(err)
+			continue
+		}
+		log.Infof("Renaming %s => %s", src, dest)
+		task.Files = append(task.Files, dest)
+	}
+	task.SupplIndex = supplIdx
+	if err := os.RemoveAll(task.StageDir); err != nil {
+		log.Warnln(err)
+	}
+	os.Remove(path.Dir(task.StageDir))
+}
+
+// fileExt return the extension of file, sniffing PDF files without suffix
+func fileExt(fn string) string {
+	ext := strings.ToLower(path.Ext(fn))
+	if ext != "" && len(ext) <= 6 && !stringo.StrDetect(ext, "[^.a-z0-9]") {
+		return ext
+	}
+	f, err := os.Open(fn)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	buf := make([]byte, 4)
+	if n, _ := f.Read(buf); n == 4 && string(buf) == "%PDF" {
+		return ".pdf"
+	}
+	return ""
+}
+
+// reserveFilename return a filename that not collides with the files
+// reserved by other DOIs, existed is true if the file is owned by the DOI in
+// a previous run, files of other DOIs are skipped
+func reserveFilename(fn string, owned map[string]bool) (newFn string, existed bool) {
+	reservedNamesLock.Lock()
+	defer reservedNamesLock.Unlock()
+	ext := path.Ext(fn)
+	base := strings.TrimSuffix(fn, ext)
+	newFn = fn
+	for i := 1; ; i++ {
+		if !reservedNames[newFn] {
+			hasFile, _ := cio.PathExists(newFn)
+			if !hasFile || bgetClis.Overwrite || owned[newFn] {
+				reservedNames[newFn] = true
+				return newFn, hasFile && !bgetClis.Overwrite
+			}
+		}
+		newFn = fmt.Sprintf("%s_%d%s", base, i, ext)
+	}
+}
+
+// previousFiles return the files of the DOI recorded in receipt.json of a previous run
+func (task *doiTask) previousFiles() map[string]bool {
+	files := make(map[string]bool)
+	buf, err := ioutil.ReadFile(task.metaFile("receipt.json"))
+	if err != nil {
+		return files
+	}
+	receipt := doiReceipt{}
+	if err = json.Unmarshal(buf, &receipt); err != nil || !strings.EqualFold(receipt.Doi, task.Doi) {
+		return files
+	}
+	for _, v := range receipt.Files {
+		files[v] = true
+	}
+	return files
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestRenderNameTemplate(t *testing.T) {
+	meta := doiNameMeta{
+		Doi:           "10.1073/pnas.1814397115",
+		FirstAuthor:   "Müller",
+		Year:          "2019",
+		JournalAbbrev: "Proc Natl Acad Sci USA",
+		Title:         "Galectins control MTOR and AMPK in response to lysosomal damage",
+	}
+	fn := renderNameTemplate("{first_author}_{year}_{journal_abbrev}_{short_title}.pdf", meta)
+	if fn != "Müller_2019_Proc_Natl_Acad_Sci_USA_Galectins-control-MTOR-and-AMPK-in.pdf" {
+		t.Errorf("unexpected filename: %s", fn)
+	}
+	if fn := renderNameTemplate("{journal}_{doi}", meta); fn != "NA_10.1073_pnas.1814397115" {
+		t.Errorf("unexpected filename: %s", fn)
+	}
+}
+
+func TestReserveFilename(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bget-name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := path.Join(dir, "Smith_2019.pdf")
+	if err = ioutil.WriteFile(fn, []byte("%PDF"), 0664); err != nil {
+		t.Fatal(err)
+	}
+	// the file of another DOI is kept
+	if newFn, existed := reserveFilename(fn, map[string]bool{}); newFn != path.Join(dir, "Smith_2019_1.pdf") || existed {
+		t.Errorf("unexpected filename: %s %v", newFn, existed)
+	}
+	// the file of the same DOI in a previous run
+	fn2 := path.Join(dir, "Smith_2020.pdf")
+	ioutil.WriteFile(fn2, []byte("%PDF"), 0664)
+	if newFn, existed := reserveFilename(fn2, map[string]bool{fn2: true}); newFn != fn2 || !existed {
+		t.Errorf("unexpected filename: %s %v", newFn, existed)
+	}
+	if newFn, _ := reserveFilename(fn2, map[string]bool{fn2: true}); newFn != path.Join(dir, "Smith_2020_1.pdf") {
+		t.Errorf("reserved name should not be reused: %s", newFn)
+	}
+}
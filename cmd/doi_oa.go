@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openanno/bget/api/types"
+)
+
+var oaOnly bool
+var doajFile string
+
+var doajJournals map[string]string
+var doajOnce sync.Once
+
+// doiAccess is the open-access status of DOI: oa, hybrid, closed or unknown
+type doiAccess struct {
+	Status string `json:"oa_status"`
+	// Source is doaj, crossref-license or preprint
+	Source     string `json:"oa_source,omitempty"`
+	License    string `json:"license,omitempty"`
+	LicenseURL string `json:"license_url,omitempty"`
+}
+
+// normalizeISSN convert ISSN to upper case without hyphen
+func normalizeISSN(issn string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(issn), "-", ""))
+}
+
+// loadDoaj index the DOAJ CSV (bget i db/journal-doaj) by ISSN
+func loadDoaj(fn string) (index map[string]string, err error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return make(map[string]string), err
+	}
+	defer f.Close()
+	return parseDoaj(f)
+}
+
+// parseDoaj return the journal license of print and online ISSNs
+func parseDoaj(r io.Reader) (index map[string]string, err error) {
+	index = make(map[string]string)
+	reader := csv.NewReader(r)
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return index, err
+	}
+	issnCols := []int{}
+	licenseCol := -1
+	for i, v := range header {
+		v = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(v), "\ufeff"))
+		if strings.Contains(v, "issn") {
+			issnCols = append(issnCols, i)
+		} else if licenseCol < 0 && strings.Contains(v, "license") {
+			licenseCol = i
+		}
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return index, err
+		}
+		license := ""
+		if licenseCol >= 0 && licenseCol < len(record) {
+			license = strings.TrimSpace(record[licenseCol])
+		}
+		for _, i := range issnCols {
+			if i < len(record) && normalizeISSN(record[i]) != "" {
+				index[normalizeISSN(record[i])] = license
+			}
+		}
+	}
+	return index, nil
+}
+
+// isOpenLicense indicates the license URL is a Creative Commons license
+func isOpenLicense(url string) bool {
+	return strings.Contains(strings.ToLower(url), "creativecommons.org")
+}
+
+// openLicense return the open license of version of record (or accepted
+// manuscript) that is in effect now, otherwise the first license
+func openLicense(licenses []types.CrossRefLicense, now time.Time) (url string, open bool) {
+	today := now.Format("2006-01-02")
+	for _, version := range []string{"vor", "am", "unspecified"} {
+		for _, v := range licenses {
+			if v.ContentVersion == version && isOpenLicense(v.URL) && v.Start.Date() <= today {
+				return v.URL, true
+			}
+		}
+	}
+	if len(licenses) > 0 {
+		return licenses[0].URL, false
+	}
+	return "", false
+}
+
+// classifyDoiAccess classify the work as oa (DOAJ journal or preprint), hybrid
+// (open license in a subscription journal), closed or unknown (no license in
+// Crossref); without the DOAJ data an open license is classified as oa
+func classifyDoiAccess(pre *preprintInfo, work *types.CrossRefWork, doaj map[string]string) doiAccess {
+	if pre != nil && !(preferPublished && pre.PublishedDoi != "") {
+		return doiAccess{Status: "oa", Source: "preprint"}
+	}
+	if work == nil {
+		return doiAccess{Status: "unknown"}
+	}
+	url, open := openLicense(work.License, time.Now())
+	access := doiAccess{Status: "closed", LicenseURL: url}
+	if open {
+		access.Source = "crossref-license"
+	}
+	for _, issn := range work.ISSN {
+		if license, ok := doaj[normalizeISSN(issn)]; ok {
+			access.Status, access.Source, access.License = "oa", "doaj", license
+			return access
+		}
+	}
+	if open && doaj == nil {
+		access.Status = "oa"
+	} else if open {
+		access.Status = "hybrid"
+	} else if len(work.License) == 0 {
+		access.Status = "unknown"
+	}
+	return access
+}
+
+// defaultDoajFile return the latest DOAJ CSV installed by bget i db/journal-doaj
+// in the download or working dir (db/journal-doaj with --autopath)
+func defaultDoajFile() string {
+	wd, _ := os.Getwd()
+	for _, dir := range []string{bgetClis.DownloadDir, wd} {
+		for _, sub := range []string{path.Join("db", "journal-doaj"), ""} {
+			for _, pattern := range []string{"journalcsv*.csv", "csv"} {
+				matches, _ := filepath.Glob(path.Join(dir, sub, pattern))
+				if len(matches) > 0 {
+					sort.Strings(matches)
+					return matches[len(matches)-1]
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// checkDoiAccess classify the open-access status of DOI before scraping
+func checkDoiAccess(doi string, pre *preprintInfo, work *types.CrossRefWork) doiAccess {
+	doajOnce.Do(func() {
+		fn := doajFile
+		if fn == "" {
+			if fn = defaultDoajFile(); fn == "" {
+				log.Infof("No DOAJ data (bget i db/journal-doaj), open licenses are classified as oa.")
+				return
+			}
+			log.Infof("Using DOAJ data %s.", fn)
+		}
+		var err error
+		if doajJournals, err = loadDoaj(fn); err != nil {
+			log.Warnf("DOAJ data %s: %v", fn, err)
+			doajJournals = nil
+		}
+	})
+	access := classifyDoiAccess(pre, work, doajJournals)
+	log.Infof("Open-access status of %s: %s.", doi, access.Status)
+	return access
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openanno/bget/api/types"
+)
+
+func TestParseDoaj(t *testing.T) {
+	input := "\ufeffJournal title,Journal ISSN (print version),Journal EISSN (online version),Journal license\n" +
+		"PLOS ONE,,1932-6203,CC BY\nJournal X,1234-567x,,\"CC BY, CC BY-NC\"\n"
+	index, err := parseDoaj(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index["19326203"] != "CC BY" || index["1234567X"] != "CC BY, CC BY-NC" || len(index) != 2 {
+		t.Errorf("unexpected index: %v", index)
+	}
+}
+
+func TestClassifyDoiAccess(t *testing.T) {
+	cc := types.CrossRefLicense{URL: "http://creativecommons.org/licenses/by/4.0/", ContentVersion: "vor"}
+	tdm := types.CrossRefLicense{URL: "https://www.springer.com/tdm", ContentVersion: "tdm"}
+	doaj := map[string]string{"19326203": "CC BY"}
+	for _, c := range []struct {
+		work *types.CrossRefWork
+		doaj map[string]string
+		want string
+	}{
+		{&types.CrossRefWork{ISSN: []string{"1932-6203"}}, doaj, "oa"},
+		{&types.CrossRefWork{ISSN: []string{"0028-0836"}, License: []types.CrossRefLicense{tdm, cc}}, doaj, "hybrid"},
+		{&types.CrossRefWork{ISSN: []string{"0028-0836"}, License: []types.CrossRefLicense{tdm, cc}}, nil, "oa"},
+		{&types.CrossRefWork{ISSN: []string{"0028-0836"}, License: []types.CrossRefLicense{tdm}}, doaj, "closed"},
+		{nil, doaj, "unknown"},
+		{&types.CrossRefWork{ISSN: []string{"0028-0836"}}, doaj, "unknown"},
+		{&types.CrossRefWork{ISSN: []string{"0028-0836"}}, nil, "unknown"},
+	} {
+		if access := classifyDoiAccess(nil, c.work, c.doaj); access.Status != c.want {
+			t.Errorf("unexpected status of %+v: %s", c.work, access.Status)
+		}
+	}
+	if access := classifyDoiAccess(&preprintInfo{Doi: "10.1101/339747"}, nil, nil); access.Status != "oa" {
+		t.Errorf("preprint should be oa: %s", access.Status)
+	}
+	embargo := types.CrossRefLicense{URL: cc.URL, ContentVersion: "am",
+		Start: types.CrossRefDateParts{DateParts: [][]int{{2099, 1, 1}}}}
+	if _, open := openLicense([]types.CrossRefLicense{embargo}, time.Now()); open {
+		t.Error("embargoed license should not be open")
+	}
+}
+
+func TestDefaultDoajFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bget-doaj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	oldDir := bgetClis.DownloadDir
+	defer func() { bgetClis.DownloadDir = oldDir }()
+	bgetClis.DownloadDir = dir
+	os.MkdirAll(path.Join(dir, "db", "journal-doaj"), 0755)
+	for _, v := range []string{"journalcsv__doaj_20200601_utf8.csv", "journalcsv__doaj_20200610_utf8.csv"} {
+		ioutil.WriteFile(path.Join(dir, "db", "journal-doaj", v), []byte("ISSN\n"), 0664)
+	}
+	if fn := defaultDoajFile(); fn != path.Join(dir, "db", "journal-doaj", "journalcsv__doaj_20200610_utf8.csv") {
+		t.Errorf("unexpected DOAJ file: %s", fn)
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/openanno/bget/spider"
+)
+
+// pidCurieRe match the compact identifiers of identifiers.org, e.g. uniprot:P12345
+var pidCurieRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9._-]*:[^/\s][^\s]*$`)
+var pidPurlRe = regexp.MustCompile(`^https?://purl[.]`)
+
+// pidNotCurieRe match the prefixes that are not identifiers.org namespaces
+var pidNotCurieRe = regexp.MustCompile(`^(https?|ftp|doi|pmid|pmcid|arxiv):`)
+
+// pidResolvers is the resolver of persistent identifiers
+var pidResolvers = map[string]string{
+	"doi":    "https://doi.org/",
+	"handle": "https://hdl.handle.net/",
+	"ark":    "https://n2t.net/",
+	"curie":  "https://identifiers.org/",
+}
+
+// parsePID detect the scheme of persistent identifiers: handle (hdl:),
+// ark (ark:/), purl (purl.org URL) and curie (identifiers.org), the value
+// is the normalized identifier, scheme is empty for other identifiers
+func parsePID(id string) (scheme string, value string) {
+	id = strings.TrimSpace(id)
+	lower := strings.ToLower(id)
+	for _, v := range []struct {
+		scheme string
+		prefix string
+	}{
+		{"handle", "hdl:"},
+		{"handle", "handle:"},
+		{"handle", "http://hdl.handle.net/"},
+		{"handle", "https://hdl.handle.net/"},
+		{"ark", "http://n2t.net/"},
+		{"ark", "https://n2t.net/"},
+		{"curie", "http://identifiers.org/"},
+		{"curie", "https://identifiers.org/"},
+	} {
+		if strings.HasPrefix(lower, v.prefix) {
+			value = strings.TrimSpace(id[len(v.prefix):])
+			if v.scheme == "handle" {
+				return v.scheme, "hdl:" + value
+			}
+			return parsePID(value)
+		}
+	}
+	switch {
+	case strings.HasPrefix(lower, "ark:"):
+		return "ark", "ark:/" + strings.TrimPrefix(strings.TrimSpace(id[len("ark:"):]), "/")
+	case pidPurlRe.MatchString(lower):
+		return "purl", id
+	case pidCurieRe.MatchString(id) && !pidNotCurieRe.MatchString(lower):
+		return "curie", id
+	}
+	return "", id
+}
+
+// isPID indicates id is a Handle, ARK, PURL or identifiers.org CURIE
+func isPID(id string) bool {
+	scheme, _ := parsePID(id)
+	return scheme != ""
+}
+
+// pidURL return the resolver URL of DOI or persistent identifier
+func pidURL(id string) string {
+	scheme, value := parsePID(id)
+	switch scheme {
+	case "":
+		return pidResolvers["doi"] + id
+	case "handle":
+		return pidResolvers[scheme] + strings.TrimPrefix(value, "hdl:")
+	case "purl":
+		return value
+	}
+	return pidResolvers[scheme] + value
+}
+
+// pidSpiders resolve the landing page of persistent identifier and run the
+// universal spider on it
+func pidSpiders(id string) (candidates []spider.DoiCandidate, opt *spider.DoiSpiderOpt) {
+	opt = newDoiSpiderOpt(id)
+	opt.PID, _ = parsePID(id)
+	if entry := loadDoiCache(opt); entry != nil {
+		return entry.Candidates, opt
+	}
+	resolveDoiURL(opt)
+	if opt.URL == nil {
+		log.Warnf("Could not resolve %s via %s.", id, pidURL(id))
+		return candidates, opt
+	}
+	log.Infof("Resolving %s => %s", id, opt.URL.String())
+	candidates = runDoiStrategies(opt)
+	labelSupplCandidates(candidates, opt)
+	saveDoiCache(opt, candidates)
+	return candidates, opt
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import "testing"
+
+func TestParsePID(t *testing.T) {
+	for id, want := range map[string][3]string{
+		"hdl:10013/epic.51096":                      {"handle", "hdl:10013/epic.51096", "https://hdl.handle.net/10013/epic.51096"},
+		"https://hdl.handle.net/1721.1/123456":      {"handle", "hdl:1721.1/123456", "https://hdl.handle.net/1721.1/123456"},
+		"ark:/13030/tf5p30086k":                     {"ark", "ark:/13030/tf5p30086k", "https://n2t.net/ark:/13030/tf5p30086k"},
+		"https://n2t.net/ark:/13030/tf5p30086k":     {"ark", "ark:/13030/tf5p30086k", "https://n2t.net/ark:/13030/tf5p30086k"},
+		"http://purl.obolibrary.org/obo/GO_0006914": {"purl", "http://purl.obolibrary.org/obo/GO_0006914", "http://purl.obolibrary.org/obo/GO_0006914"},
+		"uniprot:P12345":                            {"curie", "uniprot:P12345", "https://identifiers.org/uniprot:P12345"},
+		"https://identifiers.org/taxonomy:9606":     {"curie", "taxonomy:9606", "https://identifiers.org/taxonomy:9606"},
+		"10.1038/s41586-019-1844-5":                 {"", "10.1038/s41586-019-1844-5", "https://doi.org/10.1038/s41586-019-1844-5"},
+	} {
+		scheme, value := parsePID(id)
+		if scheme != want[0] || value != want[1] || pidURL(id) != want[2] {
+			t.Errorf("unexpected result of %s: %s %s %s", id, scheme, value, pidURL(id))
+		}
+	}
+	if idType, value := doiIDType("hdl:10013/epic.51096"); idType != "handle" || value != "hdl:10013/epic.51096" {
+		t.Errorf("unexpected type of handle: %s %s", idType, value)
+	}
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	neturl "net/url"
+	"strconv"
+	"strings"
+
+	"github.com/openanno/bget/api/fetch"
+	"github.com/openanno/bget/spider"
+	cio "github.com/openbiox/ligo/io"
+	"github.com/openbiox/ligo/stringo"
+)
+
+var allVersions bool
+var preferPublished bool
+
+// preprintHosts is the website of preprint servers
+var preprintHosts = map[string]string{
+	"arxiv":   "https://arxiv.org",
+	"biorxiv": "https://www.biorxiv.org",
+	"medrxiv": "https://www.medrxiv.org",
+}
+
+// preprintInfo is the version history of an arXiv, bioRxiv or medRxiv preprint
+type preprintInfo struct {
+	Doi    string `json:"doi"`
+	Server string `json:"server"`
+	Title  string `json:"title"`
+	// Version is the requested version, 0 means the latest version
+	Version      int               `json:"requested_version,omitempty"`
+	Versions     []preprintVersion `json:"versions"`
+	PublishedDoi string            `json:"published_doi,omitempty"`
+	JournalRef   string            `json:"journal_ref,omitempty"`
+}
+
+type preprintVersion struct {
+	Version int    `json:"version"`
+	Date    string `json:"date,omitempty"`
+	URL     string `json:"url"`
+}
+
+// splitPreprintVersion split '10.1101/339747v2' into '10.1101/339747' and 2
+func splitPreprintVersion(id string) (base string, version int) {
+	ver := stringo.StrExtract(id, "v[0-9]+$", 1)
+	if len(ver) == 0 || ver[0] == "" || len(ver[0]) == len(id) {
+		return id, 0
+	}
+	version, _ = strconv.Atoi(ver[0][1:])
+	return strings.TrimSuffix(id, ver[0]), version
+}
+
+// newPreprint query the versions of preprint DOI, nil is returned for
+// other DOIs or failed queries
+func newPreprint(doi string) *preprintInfo {
+	if strings.HasPrefix(strings.ToLower(doi), strings.ToLower(ArxivDoiPrefix)) {
+		return newArxivPreprint(doi[len(ArxivDoiPrefix):])
+	}
+	if stringo.StrDetect(doi, `^10[.]1101/([0-9]{4}[.][0-9]{2}[.][0-9]{2}[.])?[0-9]{6,}(v[0-9]+)?$`) {
+		return newBiorxivPreprint(doi)
+	}
+	return nil
+}
+
+func newArxivPreprint(id string) *preprintInfo {
+	base, version := splitPreprintVersion(id)
+	entries, err := fetch.Arxiv([]string{base}, setBapiClis())
+	if err != nil {
+		log.Warnln(err)
+		return nil
+	}
+	if len(entries) == 0 {
+		log.Warnf("arXiv:%s not found.", base)
+		return nil
+	}
+	e := entries[0]
+	pre := &preprintInfo{
+		Doi:        ArxivDoiPrefix + base,
+		Server:     "arxiv",
+		Title:      strings.Join(strings.Fields(e.Title), " "),
+		Version:    version,
+		JournalRef: e.JournalRef,
+	}
+	if fields := strings.Fields(e.Doi); len(fields) > 0 {
+		pre.PublishedDoi = fields[0]
+	}
+	entryID := e.ID
+	if i := strings.Index(entryID, "/abs/"); i >= 0 {
+		entryID = entryID[i+len("/abs/"):]
+	}
+	_, latest := splitPreprintVersion(entryID)
+	if latest == 0 {
+		latest = 1
+	}
+	for i := 1; i <= latest; i++ {
+		v := preprintVersion{
+			Version: i,
+			URL:     fmt.Sprintf("%s/pdf/%sv%d.pdf", preprintHosts["arxiv"], base, i),
+		}
+		if i == 1 && len(e.Published) >= 10 {
+			v.Date = e.Published[0:10]
+		} else if i == latest && len(e.Updated) >= 10 {
+			v.Date = e.Updated[0:10]
+		}
+		pre.Versions = append(pre.Versions, v)
+	}
+	return pre
+}
+
+func newBiorxivPreprint(doi string) *preprintInfo {
+	base, version := splitPreprintVersion(doi)
+	for _, server := range []string{"biorxiv", "medrxiv"} {
+		records, err := fetch.Biorxiv(server, base, setBapiClis())
+		if err != nil {
+			log.Warnln(err)
+			continue
+		}
+		if len(records) == 0 {
+			continue
+		}
+		pre := &preprintInfo{
+			Doi:     base,
+			Server:  server,
+			Title:   records[len(records)-1].Title,
+			Version: version,
+		}
+		for _, r := range records {
+			n, _ := strconv.Atoi(r.Version)
+			pre.Versions = append(pre.Versions, preprintVersion{
+				Version: n,
+				Date:    r.Date,
+				URL:     fmt.Sprintf("%s/content/%sv%d.full.pdf", preprintHosts[server], base, n),
+			})
+			if r.Published != "" && r.Published != "NA" {
+				pre.PublishedDoi = r.Published
+			}
+		}
+		return pre
+	}
+	log.Warnf("%s not found in bioRxiv and medRxiv.", base)
+	return nil
+}
+
+// selected return the versions to download: the requested version,
+// all versions or the latest version
+func (pre *preprintInfo) selected() []preprintVersion {
+	if allVersions {
+		return pre.Versions
+	}
+	latest := preprintVersion{}
+	for _, v := range pre.Versions {
+		if pre.Version != 0 && v.Version == pre.Version {
+			return []preprintVersion{v}
+		}
+		if v.Version > latest.Version {
+			latest = v
+		}
+	}
+	if pre.Version != 0 {
+		log.Warnf("Version %d of %s not found, using version %d.", pre.Version, pre.Doi, latest.Version)
+	}
+	if latest.URL == "" {
+		return nil
+	}
+	return []preprintVersion{latest}
+}
+
+// spider return the PDF links of selected versions, and the supplementary
+// files of bioRxiv/medRxiv preprints via CshlpSpider
+func (pre *preprintInfo) spider() (candidates []spider.DoiCandidate, opt *spider.DoiSpiderOpt) {
+	opt = newDoiSpiderOpt(pre.Doi)
+	if pre.PublishedDoi != "" {
+		log.Infof("%s is published as %s (use --prefer-published to download the peer-reviewed version).", pre.Doi, pre.PublishedDoi)
+	}
+	if opt.FullText {
+		for _, v := range pre.selected() {
+			candidates = append(candidates, spider.DoiCandidate{
+				URL:    v.URL,
+				Source: "preprint",
+				Kind:   spider.CandidateVersion,
+				Score:  spider.CandidateSourceScores["preprint"],
+			})
+		}
+	}
+	if opt.Supplementary && pre.Server != "arxiv" {
+		opt.FullText = false
+		opt.URL, _ = neturl.Parse(fmt.Sprintf("%s/content/%s", preprintHosts[pre.Server], pre.Doi))
+		suppls := spider.ScoreCandidates(spider.CshlpSpider(opt), "publisher", opt)
+		labelSupplCandidates(suppls, opt)
+		candidates = append(candidates, suppls...)
+	}
+	return candidates, opt
+}
+
+func (pre *preprintInfo) save(outfn string) {
+	buf, err := json.MarshalIndent(pre, "", "  ")
+	if err != nil {
+		log.Warnln(err)
+		return
+	}
+	log.Infof("Saving preprint versions of %s => %s", pre.Doi, outfn)
+	cio.CreateFileParDir(outfn)
+	if err = ioutil.WriteFile(outfn, buf, 0664); err != nil {
+		log.Warnln(err)
+	}
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import "testing"
+
+func TestSplitPreprintVersion(t *testing.T) {
+	cases := []struct {
+		id      string
+		base    string
+		version int
+	}{
+		{"10.1101/2020.03.22.002386v2", "10.1101/2020.03.22.002386", 2},
+		{"10.1101/339747", "10.1101/339747", 0},
+		{"arXiv:1706.03762v5", "arXiv:1706.03762", 5},
+		{"hep-th/9901001", "hep-th/9901001", 0},
+	}
+	for _, c := range cases {
+		base, version := splitPreprintVersion(c.id)
+		if base != c.base || version != c.version {
+			t.Errorf("%s: unexpected %s %d", c.id, base, version)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/openanno/bget/api/fetch"
+)
+
+var doiQuery string
+var querySource string
+
+// queryArticle is an article returned by --query
+type queryArticle struct {
+	Pmid  string
+	Pmcid string
+	Doi   string
+	Title string
+}
+
+// ID return the identifier used to download the article
+func (a queryArticle) ID() string {
+	switch {
+	case a.Doi != "":
+		return a.Doi
+	case a.Pmcid != "":
+		return a.Pmcid
+	case a.Pmid != "":
+		return "pmid:" + a.Pmid
+	}
+	return ""
+}
+
+// searchDoiQuery run --query through PubMed or Europe PMC
+func searchDoiQuery(query string, max int) (articles []queryArticle) {
+	bapiClis := setBapiClis()
+	switch querySource {
+	case "europepmc":
+		results, err := fetch.EuropePmcSearch(query, max, bapiClis)
+		if err != nil {
+			log.Warnln(err)
+		}
+		for _, v := range results {
+			articles = append(articles, queryArticle{Pmid: v.Pmid, Pmcid: v.Pmcid, Doi: v.Doi, Title: v.Title})
+		}
+	case "pubmed":
+		results, err := fetch.PubmedSearch(query, max, bapiClis)
+		if err != nil {
+			log.Warnln(err)
+		}
+		for _, v := range results {
+			articles = append(articles, queryArticle{
+				Pmid:  v.MedlineCitation.PMID,
+				Pmcid: v.ID("pmc"),
+				Doi:   v.ID("doi"),
+				Title: v.MedlineCitation.Article.ArticleTitle,
+			})
+		}
+	default:
+		log.Fatalf("Unsupported query source %s (pubmed, europepmc).", querySource)
+	}
+	log.Infof("Finding %d articles of %s (%s).", len(articles), query, querySource)
+	return articles
+}
+
+// writeQueryReport print and save the per-article table of --query
+func writeQueryReport(articles []queryArticle, idMap map[string]string, tasks map[string]*doiTask) {
+	outfn := path.Join(bgetClis.DownloadDir, "query.report.tsv")
+	lines := []string{strings.Join([]string{"pmid", "pmcid", "doi", "title", "outcome", "files"}, "\t")}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"PMID", "DOI", "Title", "Outcome"})
+	table.SetAutoWrapText(false)
+	for _, a := range articles {
+		doi := idMap[a.ID()]
+		outcome := "unresolved"
+		files := []string{}
+		if a.ID() == "" || doi == "" {
+			outcome = "unconvertible"
+		} else if task, ok := tasks[doi]; ok {
+			outcome = task.outcome()
+			files = task.Files
+		}
+		lines = append(lines, strings.Join([]string{a.Pmid, a.Pmcid, doi, a.Title, outcome, strings.Join(files, ";")}, "\t"))
+		table.Append([]string{a.Pmid, doi, shortTitle(a.Title, 8), outcome})
+	}
+	table.Render()
+	if err := os.MkdirAll(bgetClis.DownloadDir, 0755); err != nil {
+		log.Warnln(err)
+	}
+	if err := ioutil.WriteFile(outfn, []byte(strings.Join(lines, "\n")+"\n"), 0664); err != nil {
+		log.Warnln(err)
+		return
+	}
+	log.Infof("Saving query report => %s", outfn)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/openanno/bget/spider"
+	cio "github.com/openbiox/ligo/io"
+)
+
+var allCandidates bool
+var dryRun bool
+
+// doiReceipt is the record of a downloaded DOI saved as receipt.json
+type doiReceipt struct {
+	Doi        string                `json:"doi"`
+	Status     string                `json:"status,omitempty"`
+	Notices    []doiNotice           `json:"notices,omitempty"`
+	Access     *doiAccess            `json:"access,omitempty"`
+	Candidates []spider.DoiCandidate `json:"candidates"`
+	Files      []string              `json:"files"`
+	Failed     []string              `json:"failed,omitempty"`
+	Date       string                `json:"date"`
+}
+
+// selectDoiCandidates mark and return the URLs to download: the best full
+// text, the supplementary files (--suppl) and preprint versions, or all
+// candidates if --all-candidates is set, supplementary files are returned
+// in supplURLs
+func selectDoiCandidates(candidates []spider.DoiCandidate, opt *spider.DoiSpiderOpt) (urls []string, supplURLs []string) {
+	hasFullText := false
+	for i := range candidates {
+		c := &candidates[i]
+		switch {
+		case c.Kind == spider.CandidateDataset:
+			// files of dataset are staged separately
+			c.Selected = true
+			continue
+		case c.Kind == spider.CandidateSuppl:
+			c.Selected = (opt == nil || opt.Supplementary) && supplTypeAllowed(c.URL)
+		case allCandidates:
+			c.Selected = true
+		case c.Kind == spider.CandidateFullText:
+			c.Selected = !hasFullText && (opt == nil || opt.FullText)
+			hasFullText = hasFullText || c.Selected
+		case c.Kind == spider.CandidateVersion:
+			c.Selected = true
+		}
+		if c.Selected && c.Kind == spider.CandidateSuppl {
+			supplURLs = append(supplURLs, c.URL)
+		} else if c.Selected {
+			urls = append(urls, c.URL)
+		}
+	}
+	return urls, supplURLs
+}
+
+func (task *doiTask) writeReceipt() {
+	receipt := doiReceipt{
+		Doi:        task.Doi,
+		Status:     task.Status,
+		Notices:    task.Notices,
+		Access:     task.Access,
+		Candidates: task.Candidates,
+		Files:      task.Files,
+		Failed:     task.Failed,
+		Date:       time.Now().Format(time.RFC3339),
+	}
+	if receipt.Files == nil {
+		receipt.Files = []string{}
+	}
+	buf, err := json.MarshalIndent(receipt, "", "  ")
+	if err != nil {
+		log.Warnln(err)
+		return
+	}
+	outfn := task.metaFile("receipt.json")
+	cio.CreateFileParDir(outfn)
+	if err = ioutil.WriteFile(outfn, buf, 0664); err != nil {
+		log.Warnln(err)
+	}
+}
+
+// printDoiCandidates print the ranked candidates of --dry-run
+func printDoiCandidates(dois []string, tasks map[string]*doiTask) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"DOI", "Rank", "Score", "Kind", "Source", "Validated", "Selected", "URL"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	for _, doi := range dois {
+		task, ok := tasks[doi]
+		if !ok || len(task.Candidates) == 0 {
+			table.Append([]string{doi, "", "", "", "", "", "", "(no candidates)"})
+			continue
+		}
+		for i, c := range task.Candidates {
+			table.Append([]string{doi, strconv.Itoa(i + 1), fmt.Sprintf("%.2f", c.Score), c.Kind, c.Source,
+				strconv.FormatBool(c.Validated), strconv.FormatBool(c.Selected), c.URL})
+		}
+	}
+	table.Render()
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/openanno/bget/spider"
+)
+
+func TestSelectDoiCandidates(t *testing.T) {
+	candidates := []spider.DoiCandidate{
+		{URL: "https://a.org/main.pdf", Kind: spider.CandidateFullText, Score: 1},
+		{URL: "https://a.org/other.pdf", Kind: spider.CandidateFullText, Score: 0.5},
+		{URL: "https://a.org/s1.xlsx", Kind: spider.CandidateSuppl, Score: 0.8},
+		{URL: "https://a.org/figures", Kind: spider.CandidateOther, Score: 0.3},
+	}
+	opt := &spider.DoiSpiderOpt{FullText: true, Supplementary: true}
+	urls, supplURLs := selectDoiCandidates(candidates, opt)
+	if len(urls) != 1 || urls[0] != "https://a.org/main.pdf" || len(supplURLs) != 1 || supplURLs[0] != "https://a.org/s1.xlsx" {
+		t.Errorf("unexpected urls: %v %v", urls, supplURLs)
+	}
+	if candidates[1].Selected || candidates[3].Selected {
+		t.Errorf("unexpected selection: %+v", candidates)
+	}
+	// supplementary files are only selected with --suppl
+	opt.Supplementary = false
+	if urls, supplURLs = selectDoiCandidates(candidates, opt); len(urls) != 1 || len(supplURLs) != 0 || candidates[2].Selected {
+		t.Errorf("unexpected urls without --suppl: %v %v", urls, supplURLs)
+	}
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openanno/bget/api/fetch"
+	"github.com/openanno/bget/api/types"
+)
+
+var checkRetractions bool
+var retractionWatchFile string
+var downloadNotices bool
+
+var retractionWatch map[string][]doiNotice
+var retractionWatchOnce sync.Once
+
+// doiNotice is a retraction, expression of concern or correction of a paper
+type doiNotice struct {
+	Doi    string `json:"doi"`
+	Type   string `json:"type"`
+	Source string `json:"source"`
+	Date   string `json:"date,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// noticeStatus is the status of paper by notice type, from the most severe
+var noticeStatus = []struct {
+	Type   string
+	Status string
+}{
+	{"retraction", "retracted"},
+	{"withdrawal", "retracted"},
+	{"expression_of_concern", "expression-of-concern"},
+	{"correction", "corrected"},
+}
+
+// normalizeNoticeType convert the notice types of Crossref and Retraction Watch
+// to retraction, withdrawal, expression_of_concern, correction or reinstatement
+func normalizeNoticeType(t string) string {
+	t = strings.ToLower(strings.TrimSpace(t))
+	switch {
+	case strings.Contains(t, "retract"):
+		return "retraction"
+	case strings.Contains(t, "withdraw"):
+		return "withdrawal"
+	case strings.Contains(t, "concern"):
+		return "expression_of_concern"
+	case strings.Contains(t, "correct") || strings.Contains(t, "errat") || strings.Contains(t, "corrig"):
+		return "correction"
+	case strings.Contains(t, "reinstat"):
+		return "reinstatement"
+	}
+	return strings.Join(strings.Fields(strings.ReplaceAll(t, "-", " ")), "_")
+}
+
+func isNoticeType(t string) bool {
+	for _, v := range noticeStatus {
+		if v.Type == t {
+			return true
+		}
+	}
+	return t == "reinstatement"
+}
+
+// crossrefNotices collect the notices of doi from the updated-by and relation
+// fields of work and the works that update doi
+func crossrefNotices(doi string, work *types.CrossRefWork) (notices []doiNotice) {
+	if work != nil {
+		for _, v := range work.UpdatedBy {
+			notices = append(notices, doiNotice{Doi: v.DOI, Type: normalizeNoticeType(v.Type),
+				Source: "crossref", Date: v.Updated.Date()})
+		}
+		for k, rels := range work.Relation {
+			t := normalizeNoticeType(k)
+			if !isNoticeType(t) {
+				continue
+			}
+			for _, v := range rels {
+				if strings.ToLower(v.IDType) == "doi" {
+					notices = append(notices, doiNotice{Doi: v.ID, Type: t, Source: "crossref"})
+				}
+			}
+		}
+		for _, v := range work.UpdateTo {
+			log.Infof("%s is a %s notice of %s.", doi, normalizeNoticeType(v.Type), v.DOI)
+		}
+	}
+	if work == nil {
+		// DOIs not registered in Crossref (e.g. DataCite) have no notices there
+		return notices
+	}
+	works, err := fetch.CrossRefUpdates(doi, setBapiClis())
+	if err != nil {
+		log.Warnln(err)
+	}
+	for _, w := range works {
+		for _, v := range w.UpdateTo {
+			if strings.EqualFold(v.DOI, doi) {
+				notices = append(notices, doiNotice{Doi: w.DOI, Type: normalizeNoticeType(v.Type),
+					Source: "crossref", Date: v.Updated.Date()})
+			}
+		}
+	}
+	return notices
+}
+
+// loadRetractionWatch index the Retraction Watch CSV (bget i db/retraction-watch)
+// by the DOI of original papers
+func loadRetractionWatch(fn string) (index map[string][]doiNotice, err error) {
+	index = make(map[string][]doiNotice)
+	f, err := os.Open(fn)
+	if err != nil {
+		return index, err
+	}
+	defer f.Close()
+	return parseRetractionWatch(f)
+}
+
+func parseRetractionWatch(r io.Reader) (index map[string][]doiNotice, err error) {
+	index = make(map[string][]doiNotice)
+	reader := csv.NewReader(r)
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return index, err
+	}
+	col := make(map[string]int)
+	for i, v := range header {
+		col[strings.TrimPrefix(strings.TrimSpace(v), "\ufeff")] = i
+	}
+	get := func(record []string, name string) string {
+		if i, ok := col[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return index, err
+		}
+		doi := strings.ToLower(get(record, "OriginalPaperDOI"))
+		if doi == "" || doi == "unavailable" {
+			continue
+		}
+		notice := doiNotice{
+			Doi:    get(record, "RetractionDOI"),
+			Type:   normalizeNoticeType(get(record, "RetractionNature")),
+			Source: "retraction-watch",
+			Date:   retractionWatchDate(get(record, "RetractionDate")),
+			Reason: strings.Trim(get(record, "Reason"), ";+ "),
+		}
+		if strings.EqualFold(notice.Doi, "unavailable") {
+			notice.Doi = ""
+		}
+		index[doi] = append(index[doi], notice)
+	}
+	return index, nil
+}
+
+// retractionWatchDate convert the M/D/YYYY dates of Retraction Watch to
+// YYYY-MM-DD as the Crossref notices
+func retractionWatchDate(date string) string {
+	date = strings.Split(date, " ")[0]
+	if t, err := time.Parse("1/2/2006", date); err == nil {
+		return t.Format("2006-01-02")
+	}
+	return date
+}
+
+// doiNoticeStatus return the most severe status of the notices, a
+// reinstatement cancels the retraction
+func doiNoticeStatus(notices []doiNotice) string {
+	kinds := make(map[string]bool)
+	for _, v := range notices {
+		kinds[v.Type] = true
+	}
+	for _, v := range noticeStatus {
+		if kinds[v.Type] && !(v.Status == "retracted" && kinds["reinstatement"]) {
+			return v.Status
+		}
+	}
+	return ""
+}
+
+// checkRetraction flag the retractions, expressions of concern and corrections of task
+func (task *doiTask) checkRetraction(doi string, work *types.CrossRefWork) {
+	notices := crossrefNotices(doi, work)
+	if retractionWatchFile != "" {
+		retractionWatchOnce.Do(func() {
+			var err error
+			if retractionWatch, err = loadRetractionWatch(retractionWatchFile); err != nil {
+				log.Warnf("Retraction Watch data %s: %v", retractionWatchFile, err)
+			}
+		})
+		notices = append(notices, retractionWatch[strings.ToLower(doi)]...)
+	}
+	seen := make(map[string]bool)
+	for _, v := range notices {
+		key := strings.ToLower(v.Doi) + v.Type
+		if v.Doi != "" && seen[key] {
+			continue
+		}
+		seen[key] = true
+		task.Notices = append(task.Notices, v)
+	}
+	sort.SliceStable(task.Notices, func(i, j int) bool {
+		return task.Notices[i].Date < task.Notices[j].Date
+	})
+	task.Status = doiNoticeStatus(task.Notices)
+	if task.Status == "" {
+		return
+	}
+	var dois []string
+	for _, v := range task.Notices {
+		if v.Doi != "" {
+			dois = append(dois, v.Type+": "+v.Doi)
+		}
+	}
+	log.Warnf("%s is %s (%s).", doi, strings.ToUpper(task.Status), strings.Join(dois, ", "))
+}
+
+// noticeURLs return the full text URLs of the notices used by --download-notices
+func (task *doiTask) noticeURLs() (urls []string) {
+	for _, v := range task.Notices {
+		if v.Doi == "" || v.Type == "reinstatement" {
+			continue
+		}
+		candidates, opt := doiSpiders(v.Doi)
+		if opt != nil {
+			opt.Supplementary = false
+		}
+		noticeURLs, _ := selectDoiCandidates(candidates, opt)
+		urls = append(urls, noticeURLs...)
+	}
+	return urls
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRetractionWatch(t *testing.T) {
+	data := "\ufeffRecord ID,Title,RetractionDate,RetractionDOI,OriginalPaperDOI,RetractionNature,Reason\n" +
+		`1,"A paper, retracted",3/5/2020 0:00,10.1000/notice.1,10.1000/Paper.1,Retraction,+Fabrication of Data;` + "\n" +
+		`2,Another paper,1/2/2021 0:00,unavailable,10.1000/paper.2,Expression of concern,+Concerns about Data;` + "\n" +
+		`3,No DOI,1/2/2021 0:00,10.1000/notice.3,unavailable,Correction,` + "\n"
+	index, err := parseRetractionWatch(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index) != 2 {
+		t.Fatalf("unexpected index: %+v", index)
+	}
+	n := index["10.1000/paper.1"]
+	if len(n) != 1 || n[0].Doi != "10.1000/notice.1" || n[0].Type != "retraction" || n[0].Date != "2020-03-05" || n[0].Reason != "Fabrication of Data" {
+		t.Errorf("unexpected notices: %+v", n)
+	}
+	if doiNoticeStatus(index["10.1000/paper.2"]) != "expression-of-concern" || index["10.1000/paper.2"][0].Doi != "" {
+		t.Errorf("unexpected notices: %+v", index["10.1000/paper.2"])
+	}
+}
+
+func TestDoiNoticeStatus(t *testing.T) {
+	notices := []doiNotice{{Type: normalizeNoticeType("Correction")}, {Type: normalizeNoticeType("retraction")}}
+	if doiNoticeStatus(notices) != "retracted" {
+		t.Errorf("unexpected status: %s", doiNoticeStatus(notices))
+	}
+	notices = append(notices, doiNotice{Type: normalizeNoticeType("Reinstatement")})
+	if doiNoticeStatus(notices) != "corrected" {
+		t.Errorf("unexpected status: %s", doiNoticeStatus(notices))
+	}
+	if normalizeNoticeType("expression_of_concern") != "expression_of_concern" || normalizeNoticeType("Erratum") != "correction" {
+		t.Error("unexpected notice type")
+	}
+}
+
+func TestAddJSONField(t *testing.T) {
+	for in, want := range map[string]string{
+		` {"doi_records": {}}`: `{"status":"retracted","doi_records": {}}`,
+		`{ }`:                  `{"status":"retracted"}`,
+		`<doi_records/>`:       `<doi_records/>`,
+	} {
+		if got := string(addJSONField([]byte(in), "status", "retracted")); got != want {
+			t.Errorf("unexpected JSON of %s: %s", in, got)
+		}
+	}
+	if got := string(addJSONField([]byte(`{"a": 1}`), "status", "")); got != `{"a": 1}` {
+		t.Errorf("empty status should not be added: %s", got)
+	}
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openanno/bget/chrome"
+	"github.com/openanno/bget/spider"
+)
+
+var doiDeadline int
+
+// chromeStrategy render the landing page with the headless browser, it is
+// only used when the static spiders return nothing
+var chromeStrategy = doiStrategy{Name: "chrome", Source: "chrome", Publisher: true, Spider: spider.ChromeSpider}
+
+// doiStrategy is one way to resolve the files of DOI
+type doiStrategy struct {
+	Name string
+	// Source is the candidate source used in scoring
+	Source string
+	// Publisher indicates the strategy visits the publisher website
+	// that provides the supplementary files
+	Publisher bool
+	Spider    func(opt *spider.DoiSpiderOpt) []string
+}
+
+// doiStrategyResult is the candidates returned by one strategy
+type doiStrategyResult struct {
+	Strategy doiStrategy
+	Opt      *spider.DoiSpiderOpt
+	URLs     []string
+	// Valid indicates the first candidate is a validated PDF
+	Valid bool
+}
+
+// doiStrategies return the enabled strategies of DOI prefix, only the
+// universal spider is used for the other persistent identifiers
+func doiStrategies(doiOrg string, pid string) (strategies []doiStrategy) {
+	if pid != "" {
+		return []doiStrategy{{Name: "universal", Source: "universal", Publisher: true, Spider: spider.UniVersalDoiSpider}}
+	}
+	if pmc {
+		strategies = append(strategies, doiStrategy{Name: "pmc", Source: "pmc", Spider: spider.PmcSpider})
+	}
+	if fn, ok := spider.DoiSpidersPool[doiOrg]; ok {
+		strategies = append(strategies, doiStrategy{Name: "pool", Source: "publisher", Publisher: true, Spider: fn})
+	}
+	if universeSpider {
+		strategies = append(strategies, doiStrategy{Name: "universal", Source: "universal", Publisher: true, Spider: spider.UniVersalDoiSpider})
+	}
+	if scihub {
+		strategies = append(strategies, doiStrategy{Name: "scihub", Source: "scihub", Spider: spider.ScihupSpider})
+	}
+	return strategies
+}
+
+// cloneDoiSpiderOpt copy opt with a new CitationMeta, so that strategies
+// can run concurrently
+func cloneDoiSpiderOpt(opt *spider.DoiSpiderOpt) *spider.DoiSpiderOpt {
+	newOpt := *opt
+	citationMeta := make(map[string]string)
+	newOpt.CitationMeta = &citationMeta
+	if opt.URL != nil {
+		u := *opt.URL
+		newOpt.URL = &u
+	}
+	return &newOpt
+}
+
+// runStrategy run the spider with retries until it returns candidates
+// or the context is done
+func runStrategy(ctx context.Context, s doiStrategy, opt *spider.DoiSpiderOpt) (urls []string) {
+	for t := 0; t < bgetClis.Retries+1; t++ {
+		urls = s.Spider(opt)
+		if len(urls) > 0 || t == bgetClis.Retries {
+			break
+		}
+		log.Warnf("%s spider: %s returns empty, on attempt %d... retrying after %d seconds.", s.Name, opt.Doi, t+1, bgetClis.RetSleepTime)
+		select {
+		case <-ctx.Done():
+			return urls
+		case <-time.After(time.Duration(bgetClis.RetSleepTime) * time.Second):
+		}
+	}
+	return urls
+}
+
+// runDoiStrategies run the strategies concurrently under the deadline, the
+// spiders of the rest are stopped once a validated PDF is found (unless
+// supplementary files are required), and return the ranked candidates
+func runDoiStrategies(opt *spider.DoiSpiderOpt) (candidates []spider.DoiCandidate) {
+	strategies := doiStrategies(strings.Split(opt.Doi, "/")[0], opt.PID)
+	if len(strategies) == 0 {
+		return candidates
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(doiDeadline)*time.Second)
+	defer cancel()
+	ch := make(chan doiStrategyResult, len(strategies))
+	// the universal spider visits the same publisher website as the pool
+	// spider, so it waits for the pool spider and is skipped after a PDF
+	poolDone := make(chan bool, 1)
+	hasPool := false
+	for _, s := range strategies {
+		hasPool = hasPool || s.Name == "pool"
+	}
+	for _, s := range strategies {
+		go func(s doiStrategy) {
+			sOpt := cloneDoiSpiderOpt(opt)
+			sOpt.Context = ctx
+			if !s.Publisher {
+				sOpt.Supplementary = false
+			}
+			if s.Name == "universal" && hasPool {
+				select {
+				case <-ctx.Done():
+					ch <- doiStrategyResult{Strategy: s, Opt: sOpt}
+					return
+				case valid := <-poolDone:
+					if valid {
+						ch <- doiStrategyResult{Strategy: s, Opt: sOpt}
+						return
+					}
+				}
+			}
+			ret := doiStrategyResult{Strategy: s, Opt: sOpt, URLs: runStrategy(ctx, s, sOpt)}
+			if opt.FullText && len(ret.URLs) > 0 && ctx.Err() == nil {
+				ret.URLs, ret.Valid = validFirst(ctx, ret.URLs)
+			}
+			if s.Name == "pool" {
+				poolDone <- ret.Valid
+			}
+			ch <- ret
+		}(s)
+	}
+	var results []doiStrategyResult
+	hasValid := false
+	hasPublisher := false
+wait:
+	for range strategies {
+		select {
+		case <-ctx.Done():
+			log.Warnf("Reaching the deadline (%ds) of %s.", doiDeadline, opt.Doi)
+			break wait
+		case ret := <-ch:
+			if len(ret.URLs) == 0 {
+				continue
+			}
+			log.Infof("Strategy %s of %s returns %d candidates (validated PDF: %v).", ret.Strategy.Name, opt.Doi, len(ret.URLs), ret.Valid)
+			results = append(results, ret)
+			hasValid = hasValid || ret.Valid
+			hasPublisher = hasPublisher || ret.Strategy.Publisher
+			if hasValid && (!opt.Supplementary || hasPublisher) {
+				break wait
+			}
+		}
+	}
+	cancel()
+	if len(results) == 0 && chrome.Enabled() {
+		log.Infof("Static spiders of %s return nothing, trying the headless browser.", opt.Doi)
+		sOpt := cloneDoiSpiderOpt(opt)
+		if urls := chromeStrategy.Spider(sOpt); len(urls) > 0 {
+			results = append(results, doiStrategyResult{Strategy: chromeStrategy, Opt: sOpt, URLs: urls})
+		}
+	}
+	for _, ret := range results {
+		scored := spider.ScoreCandidates(ret.URLs, ret.Strategy.Source, ret.Opt)
+		if ret.Valid {
+			scored[0].Validated = true
+			scored[0].Score += 0.1
+		}
+		candidates = append(candidates, scored...)
+	}
+	mergeCitationMeta(opt, results)
+	return spider.RankCandidates(candidates)
+}
+
+// validFirst move the first validated PDF to the front of urls, the
+// requests are stopped once ctx is done
+func validFirst(ctx context.Context, urls []string) ([]string, bool) {
+	for i, v := range urls {
+		if i > 2 || ctx.Err() != nil {
+			break
+		}
+		if checkCandidate(ctx, v).PDF {
+			return append([]string{v}, append(append([]string{}, urls[0:i]...), urls[i+1:]...)...), true
+		}
+	}
+	return urls, false
+}
+
+// mergeCitationMeta copy the website metadata of strategies into opt,
+// the publisher websites are preferred
+func mergeCitationMeta(opt *spider.DoiSpiderOpt, results []doiStrategyResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Strategy.Publisher && !results[j].Strategy.Publisher
+	})
+	for _, ret := range results {
+		for k, v := range *ret.Opt.CitationMeta {
+			if (*opt.CitationMeta)[k] == "" {
+				(*opt.CitationMeta)[k] = v
+			}
+		}
+		if ret.Strategy.Publisher && ret.Opt.URL != nil {
+			opt.URL = ret.Opt.URL
+		}
+	}
+}
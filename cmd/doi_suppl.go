@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/openanno/bget/spider"
+	cio "github.com/openbiox/ligo/io"
+	cnet "github.com/openbiox/ligo/net"
+)
+
+var supplTypes string
+var supplUnzip bool
+
+// supplEntry is one supplementary file recorded in supplementary.json
+type supplEntry struct {
+	URL       string   `json:"url"`
+	File      string   `json:"file"`
+	Label     string   `json:"label,omitempty"`
+	Caption   string   `json:"caption,omitempty"`
+	MIME      string   `json:"mime"`
+	Size      int64    `json:"size"`
+	Extracted []string `json:"extracted,omitempty"`
+}
+
+// labelSupplCandidates attach the labels collected by spiders to the
+// supplementary candidates
+func labelSupplCandidates(candidates []spider.DoiCandidate, opt *spider.DoiSpiderOpt) {
+	if opt == nil || opt.SupplLabels == nil {
+		return
+	}
+	for i := range candidates {
+		if candidates[i].Kind != spider.CandidateSuppl || candidates[i].Label != "" {
+			continue
+		}
+		label := opt.SupplLabels.Get(candidates[i].URL)
+		candidates[i].Label, candidates[i].Caption = label.Label, label.Caption
+	}
+}
+
+// supplExt return the lower-case extension of link or file without dot
+func supplExt(link string) string {
+	if u, err := neturl.Parse(link); err == nil {
+		link = u.Path
+	}
+	return strings.TrimPrefix(strings.ToLower(path.Ext(link)), ".")
+}
+
+// supplTypeAllowed check link against --suppl-types, links without
+// an extension are checked again after downloading
+func supplTypeAllowed(link string) bool {
+	if supplTypes == "" {
+		return true
+	}
+	ext := supplExt(link)
+	if ext == "" || len(ext) > 6 {
+		return true
+	}
+	for _, v := range strings.Split(supplTypes, ",") {
+		if strings.TrimPrefix(strings.ToLower(strings.TrimSpace(v)), ".") == ext {
+			return true
+		}
+	}
+	return false
+}
+
+func (task *doiTask) supplDir() string {
+	if task.Prefix == "" {
+		return path.Join(task.OutDir, "suppl")
+	}
+	return path.Join(task.OutDir, "suppl", task.Prefix)
+}
+
+// finalizeSuppl filter, rename (--name-template) and unpack the downloaded
+// supplementary files and write the supplementary.json index
+func (task *doiTask) finalizeSuppl() {
+	if len(task.SupplURLs) == 0 {
+		return
+	}
+	var owned map[string]bool
+	if nameTemplate != "" {
+		owned = task.previousFiles()
+	}
+	labels := make(map[string]spider.DoiCandidate)
+	for _, c := range task.Candidates {
+		labels[c.URL] = c
+	}
+	var entries []supplEntry
+	for _, url := range task.SupplURLs {
+		fn := path.Join(task.supplDir(), cnet.FormatURLfileName(url, bgetClis.RemoteName, bgetClis.Timeout, bgetClis.Proxy))
+		hasFile, _ := cio.PathExists(fn)
+		hasSt, _ := cio.PathExists(fn + ".st")
+		if !hasFile || hasSt {
+			continue
+		}
+		if !supplTypeAllowed(fn) {
+			log.Infof("Removing %s (not in --suppl-types %s).", fn, supplTypes)
+			os.Remove(fn)
+			continue
+		}
+		if nameTemplate != "" {
+			// the same names as the supplementary files of task.finalize
+			task.SupplIndex++
+			dest, existed := reserveFilename(path.Join(task.OutDir,
+				sanitizeFilename(fmt.Sprintf("%s_S%d", task.Prefix, task.SupplIndex))+fileExt(fn)), owned)
+			if existed {
+				log.Infof("%s existed.", dest)
+				os.Remove(fn)
+			} else if err := os.Rename(fn, dest); err != nil {
+				log.Warnln(err)
+				continue
+			} else {
+				log.Infof("Renaming %s => %s", fn, dest)
+			}
+			fn = dest
+		}
+		entry := supplEntry{URL: url, File: fn, Label: labels[url].Label, Caption: labels[url].Caption}
+		if info, err := os.Stat(fn); err == nil {
+			entry.Size = info.Size()
+		}
+		entry.MIME = detectMIME(fn)
+		if supplUnzip && (supplExt(fn) == "zip" || entry.MIME == "application/zip") {
+			dest := strings.TrimSuffix(fn, ".zip") + "_unzipped"
+			extracted, err := unzipFile(fn, dest)
+			if err != nil {
+				log.Warnf("Unpacking %s: %v", fn, err)
+			} else {
+				log.Infof("Unpacking %s => %s (%d files)", fn, dest, len(extracted))
+			}
+			entry.Extracted = extracted
+		}
+		task.Files = append(task.Files, fn)
+		entries = append(entries, entry)
+	}
+	outfn := path.Join(task.supplDir(), "supplementary.json")
+	if nameTemplate != "" {
+		outfn = task.metaFile("supplementary.json")
+		os.Remove(task.supplDir())
+		os.Remove(path.Join(task.OutDir, "suppl"))
+	}
+	if len(entries) == 0 {
+		return
+	}
+	buf, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		log.Warnln(err)
+		return
+	}
+	if err = ioutil.WriteFile(outfn, buf, 0664); err != nil {
+		log.Warnln(err)
+	}
+}
+
+// detectMIME sniff the MIME type of file, the extension is used if the
+// content is not recognized
+func detectMIME(fn string) string {
+	f, err := os.Open(fn)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	buf := make([]byte, 512)
+	n, _ := f.Read(buf)
+	sniffed := strings.Split(http.DetectContentType(buf[0:n]), ";")[0]
+	if sniffed != "application/octet-stream" && sniffed != "text/plain" && sniffed != "application/zip" {
+		return sniffed
+	}
+	if byExt := mime.TypeByExtension(path.Ext(fn)); byExt != "" {
+		return strings.Split(byExt, ";")[0]
+	}
+	return sniffed
+}
+
+// unzipFile extract the zip archive fn into dest
+func unzipFile(fn string, dest string) (files []string, err error) {
+	r, err := zip.OpenReader(fn)
+	if err != nil {
+		return files, err
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		target := filepath.Join(dest, f.Name)
+		if !strings.HasPrefix(target, filepath.Clean(dest)+string(os.PathSeparator)) {
+			return files, fmt.Errorf("illegal file path in zip: %s", f.Name)
+		}
+		if f.FileInfo().IsDir() {
+			os.MkdirAll(target, 0755)
+			continue
+		}
+		if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return files, err
+		}
+		if err = extractZipFile(f, target); err != nil {
+			return files, err
+		}
+		files = append(files, target)
+	}
+	return files, nil
+}
+
+func extractZipFile(f *zip.File, target string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, rc)
+	return err
+}
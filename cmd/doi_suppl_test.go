@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	cio "github.com/openbiox/ligo/io"
+)
+
+func TestSupplTypeAllowed(t *testing.T) {
+	supplTypes = "xlsx, .CSV"
+	defer func() { supplTypes = "" }()
+	for link, allowed := range map[string]bool{
+		"https://a.org/s1.xlsx":           true,
+		"https://a.org/s2.csv?download=1": true,
+		"https://a.org/s3.docx":           false,
+		"https://a.org/suppl?file=3":      true,
+	} {
+		if supplTypeAllowed(link) != allowed {
+			t.Errorf("unexpected result of %s", link)
+		}
+	}
+}
+
+func TestUnzipFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bget-suppl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := path.Join(dir, "mmc1.zip")
+	f, err := os.Create(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for _, name := range []string{"tables/s1.csv", "readme.txt"} {
+		fw, _ := w.Create(name)
+		fw.Write([]byte("a,b\n1,2\n"))
+	}
+	w.Close()
+	f.Close()
+	files, err := unzipFile(fn, path.Join(dir, "mmc1"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("unexpected files: %v %v", files, err)
+	}
+	if detectMIME(fn) != "application/zip" {
+		t.Errorf("unexpected MIME type: %s", detectMIME(fn))
+	}
+}
+
+func TestFinalizeSupplNameTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bget-suppl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	oldTemplate := nameTemplate
+	defer func() { nameTemplate = oldTemplate }()
+	nameTemplate = "{first_author}_{year}.pdf"
+	task := &doiTask{Doi: "10.9999/bget.1", OutDir: dir, Prefix: "Smith_2019", SupplIndex: 1,
+		SupplURLs: []string{"https://a.org/files/table1.xlsx", "https://a.org/files/data"}}
+	os.MkdirAll(task.supplDir(), 0755)
+	ioutil.WriteFile(path.Join(task.supplDir(), "table1.xlsx"), []byte("a"), 0664)
+	ioutil.WriteFile(path.Join(task.supplDir(), "data"), []byte("b"), 0664)
+	task.finalizeSuppl()
+	if len(task.Files) != 2 || task.Files[0] != path.Join(dir, "Smith_2019_S2.xlsx") || task.Files[1] != path.Join(dir, "Smith_2019_S3") {
+		t.Errorf("unexpected files: %v", task.Files)
+	}
+	if hasIndex, _ := cio.PathExists(path.Join(dir, "Smith_2019.supplementary.json")); !hasIndex {
+		t.Error("supplementary.json is not written")
+	}
+}
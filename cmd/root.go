@@ -23,6 +23,7 @@ type bgetCliT struct {
 	Keys               string
 	Seqs               string
 	GdcToken           string
+	Email              string
 	Uncompress         bool
 	KeysAll            bool
 	Clean              bool
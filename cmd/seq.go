@@ -6,6 +6,7 @@ import (
 	"github.com/openanno/bget/spider"
 	cio "github.com/openbiox/ligo/io"
 	cnet "github.com/openbiox/ligo/net"
+	"github.com/openbiox/ligo/slice"
 	"github.com/openbiox/ligo/stringo"
 	"github.com/spf13/cobra"
 )
@@ -45,8 +46,10 @@ func parseSeq() (seqs map[string][]string) {
 	for i := range seqsTmp {
 		if stringo.StrDetect(strings.ToUpper(seqsTmp[i]), "^GSE|^GPL|^GDS|^GSM") {
 			seqs["geo"] = append(seqs["geo"], seqsTmp[i])
-		} else if stringo.StrDetect(strings.ToUpper(seqsTmp[i]), "^SRR|^ERR") {
-			seqs["sra"] = append(seqs["sra"], seqsTmp[i])
+		} else if isInsdcRun(seqsTmp[i]) {
+			seqs["sra"] = append(seqs["sra"], strings.ToUpper(strings.TrimSpace(seqsTmp[i])))
+		} else if isInsdcExpandable(seqsTmp[i]) {
+			seqs["insdc"] = append(seqs["insdc"], strings.ToUpper(strings.TrimSpace(seqsTmp[i])))
 		} else if stringo.StrDetect(strings.ToLower(seqsTmp[i]), ".krt$") {
 			seqs["sraKrt"] = append(seqs["sraKrt"], seqsTmp[i])
 		} else if stringo.StrDetect(strings.ToUpper(seqsTmp[i]), "^EGAD") {
@@ -67,6 +70,51 @@ func downloadSeq() {
 	done := make(map[string][]string)
 	sem := make(chan bool, bgetClis.Thread)
 	netOpt := setNetParams(&bgetClis)
+	var infos []runInfo
+	if len(seqs["insdc"]) > 0 {
+		runs, expanded := expandSeqAccessions(seqs["insdc"])
+		printRunTable(expanded)
+		infos = expanded
+		seqs["sra"] = slice.DropSliceDup(append(seqs["sra"], runs...))
+		delete(seqs, "insdc")
+	}
+	if len(seqs["sra"]) > 0 || len(seqs["geo"]) > 0 {
+		infos = collectRunInfo(seqs, infos)
+		runs := requestedRuns(seqs["sra"], infos)
+		if runFiltersEnabled() || seqDryRun {
+			all := infos
+			var reasons map[string]string
+			infos, reasons = filterRunInfo(all)
+			if seqDryRun {
+				markUnrequestedRuns(runs, all, reasons)
+				printRunSelection(all, reasons)
+				return
+			}
+			runs = selectedRuns(runs, all, reasons)
+		}
+		seqs["sra"] = runs
+		writeRunInfo(infos)
+		if sampleSheet != "" {
+			writeSampleSheet(infos)
+		}
+	}
+	if geoParse {
+		for _, v := range seqs["geo"] {
+			parseGeoSeries(v, netOpt)
+		}
+	}
+	if metadataOnly {
+		return
+	}
+	switch seqSource {
+	case "", "sra":
+	case "ena":
+		if len(seqs["sra"]) > 0 {
+			seqs["sra"] = downloadEnaRuns(seqs["sra"], netOpt)
+		}
+	default:
+		log.Fatalf("Unsupported source %s (sra, ena).", seqSource)
+	}
 	for k, v := range seqs {
 		for i := range v {
 			sem <- true
@@ -121,10 +169,25 @@ func init() {
 	SeqCmd.Flags().StringVarP(&(bgetClis.EgaCredFile), "token-file-ega", "", "", `Credential file to access EGA archive files, {"username": "{your_user_name}", 
   "password": "{your_password}","client_secret":"AMenuDLjVdVo4BSwi0QD54LL6NeVDEZRzEQUJ7h
 	JOM3g4imDZBHHX0hNfKHPeQIGkskhtCmqAJtt_jm7EKq-rWw"}.`)
+	SeqCmd.Flags().StringVarP(&seqSource, "source", "", "sra", "source of SRR/ERR/DRR runs: sra (prefetch) or ena (FASTQ with md5 checks, falling back to prefetch).")
+	SeqCmd.Flags().StringVarP(&enaProtocol, "ena-protocol", "", "https", "protocol of ENA files: https, http or ftp.")
+	SeqCmd.Flags().BoolVarP(&metadataOnly, "metadata-only", "", false, "only write runinfo.tsv (and the samplesheet) without downloading.")
+	SeqCmd.Flags().StringVarP(&sampleSheet, "samplesheet", "", "", "write samplesheet.csv of FASTQ files for pipelines: nf-core/rnaseq.")
+	SeqCmd.Flags().BoolVarP(&geoRuns, "geo-runs", "", false, "download the SRA runs of GEO series as well.")
+	SeqCmd.Flags().StringArrayVarP(&runFilterArgs, "filter", "", nil, "only download runs matching key=value of runinfo.tsv columns or sample attributes, e.g. library_strategy=RNA-Seq (GEO series files are not filtered).")
+	SeqCmd.Flags().StringVarP(&maxBases, "max-bases", "", "", "skip runs with more bases, e.g. 5G.")
+	SeqCmd.Flags().StringVarP(&runLayout, "layout", "", "", "only download runs of library layout: PAIRED or SINGLE.")
+	SeqCmd.Flags().BoolVarP(&seqDryRun, "dry-run", "", false, "print the selected and skipped runs without downloading.")
+	SeqCmd.Flags().BoolVarP(&geoParse, "geo-parse", "", false, "parse the SOFT and series matrix of GEO series into {gse}.samples.tsv (characteristics and SRA runs), {gse}.runs.tsv and expression TSV.")
 	setGlobalFlag(SeqCmd, &bgetClis)
 	setKeyListFlag(SeqCmd, &bgetClis, "accession ids")
 	SeqCmd.Example = `  bget seq ERR3324530 SRR544879 # download files from SRA databaes
   bget seq GSE23543 GSM1098572 -t 2 # download files from GEO databaes (auto download SRA acc list and run info)
+  bget seq ERR3324530 SRR544879 --source ena -t 4 # download gzipped FASTQ files from ENA (md5 verified)
+  bget seq PRJNA257197 SRP045416 SRX2676910 SAMN05201591 DRR000001 # expand studies, projects, samples and experiments to runs
+  bget seq GSE23543 PRJNA257197 --metadata-only --samplesheet nf-core/rnaseq # runinfo.tsv and samplesheet.csv of FASTQ URLs
+  bget seq GSE23543 --geo-runs --filter 'library_strategy=RNA-Seq' --filter 'organism=Homo sapiens' --max-bases 5G --layout PAIRED --dry-run
+  bget seq GSE23543 --geo-parse --metadata-only # sample characteristics, GSM to SRR runs and expression matrix TSV
   bget seq dbgap.krt # download files from dbGap database using krt files
   bget seq EGAD00001000951 # download dataset from EGA databaes
   bget seq EGAF00000585895 # download file from EGA databaes
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/openanno/bget/api/fetch"
+	"github.com/openanno/bget/api/types"
+	cio "github.com/openbiox/ligo/io"
+	cnet "github.com/openbiox/ligo/net"
+)
+
+var seqSource string
+var enaProtocol string
+
+// enaFile is a FASTQ (or submitted) file of ENA run
+type enaFile struct {
+	Run string
+	URL string
+	MD5 string
+}
+
+func (f enaFile) dest() string {
+	return path.Join(bgetClis.DownloadDir, f.Run, path.Base(f.URL))
+}
+
+// enaURL add the scheme of --ena-protocol to the ftp.sra.ebi.ac.uk paths of ENA
+func enaURL(link string) string {
+	if strings.Contains(link, "://") {
+		return link
+	}
+	return enaProtocol + "://" + link
+}
+
+// enaRunFiles return the FASTQ files of run, or the submitted files if ENA
+// has no FASTQ
+func enaRunFiles(record types.EnaRecord) (files []enaFile) {
+	urls, md5s := record.List("fastq_ftp"), record.List("fastq_md5")
+	if len(urls) == 0 {
+		urls, md5s = record.List("submitted_ftp"), record.List("submitted_md5")
+	}
+	for i, v := range urls {
+		f := enaFile{Run: record["run_accession"], URL: enaURL(v)}
+		if i < len(md5s) {
+			f.MD5 = md5s[i]
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// queryEnaRuns query the files of runs via ENA filereport, runs without a
+// copy in ENA are returned in missing
+func queryEnaRuns(runs []string) (files []enaFile, missing []string) {
+	var lock sync.Mutex
+	sem := make(chan bool, bgetClis.Thread)
+	bapiClis := setBapiClis()
+	for _, run := range runs {
+		sem <- true
+		go func(run string) {
+			defer func() {
+				<-sem
+			}()
+			records, err := fetch.EnaFileReport(run, fetch.EnaRunFields, bapiClis)
+			if err != nil {
+				log.Warnf("ENA filereport of %s: %v", run, err)
+			}
+			runFiles := []enaFile{}
+			for _, r := range records {
+				runFiles = append(runFiles, enaRunFiles(r)...)
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if len(runFiles) == 0 {
+				missing = append(missing, run)
+				return
+			}
+			files = append(files, runFiles...)
+		}(run)
+	}
+	for i := 0; i < cap(sem); i++ {
+		sem <- true
+	}
+	return files, missing
+}
+
+// downloadEnaFiles download files in parallel and verify the md5, the
+// failed files are downloaded again once
+func downloadEnaFiles(files []enaFile, netOpt *cnet.Params) (failed []enaFile) {
+	for t := 0; t < 2 && len(files) > 0; t++ {
+		urls := []string{}
+		destDirs := []string{}
+		for _, f := range files {
+			urls = append(urls, f.URL)
+			destDirs = append(destDirs, path.Dir(f.dest()))
+		}
+		cnet.HTTPGetURLs(urls, destDirs, netOpt)
+		failed = checkEnaFiles(files)
+		files = failed
+	}
+	return failed
+}
+
+// checkEnaFiles return the files that are missing, incomplete (.st) or
+// fail the md5 check, the corrupted files are removed
+func checkEnaFiles(files []enaFile) (failed []enaFile) {
+	for _, f := range files {
+		hasFile, _ := cio.PathExists(f.dest())
+		hasSt, _ := cio.PathExists(f.dest() + ".st")
+		if !hasFile || hasSt {
+			log.Warnf("%s is not completely downloaded.", f.dest())
+			failed = append(failed, f)
+			continue
+		}
+		if f.MD5 == "" {
+			continue
+		}
+		if ok, err := verifyChecksum(f.dest(), "md5:"+f.MD5); err != nil || !ok {
+			log.Warnf("md5 of %s does not match %s (%v).", f.dest(), f.MD5, err)
+			os.Remove(f.dest())
+			failed = append(failed, f)
+		}
+	}
+	return failed
+}
+
+// downloadEnaRuns fetch the FASTQ files of runs from ENA and return the
+// runs that ENA has no copy of or failed to download
+func downloadEnaRuns(runs []string, netOpt *cnet.Params) (missing []string) {
+	files, missing := queryEnaRuns(runs)
+	if len(missing) > 0 {
+		log.Infof("ENA has no copy of %s, falling back to prefetch.", strings.Join(missing, ", "))
+	}
+	if len(files) == 0 {
+		return missing
+	}
+	log.Infof("Downloading %d files of %d runs from ENA.", len(files), len(runs)-len(missing))
+	failed := downloadEnaFiles(files, netOpt)
+	if len(failed) == 0 {
+		log.Infof("Downloaded and checked %d files.", len(files))
+		return missing
+	}
+	failedRuns := enaFileRuns(failed)
+	log.Warnf("%d files of %s failed to download from ENA, falling back to prefetch.", len(failed), strings.Join(failedRuns, ", "))
+	return append(missing, failedRuns...)
+}
+
+// enaFileRuns return the unique runs of files in order
+func enaFileRuns(files []enaFile) (runs []string) {
+	seen := make(map[string]bool)
+	for _, f := range files {
+		if !seen[f.Run] {
+			seen[f.Run] = true
+			runs = append(runs, f.Run)
+		}
+	}
+	return runs
+}
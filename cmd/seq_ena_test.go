@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/openanno/bget/api/fetch"
+)
+
+func TestEnaRunFiles(t *testing.T) {
+	enaProtocol = "https"
+	defer func() {
+		enaProtocol = ""
+	}()
+	report := "run_accession\tfastq_ftp\tfastq_md5\tsubmitted_ftp\tsubmitted_md5\n" +
+		"SRR544879\tftp.sra.ebi.ac.uk/vol1/fastq/SRR544/SRR544879/SRR544879_1.fastq.gz;ftp.sra.ebi.ac.uk/vol1/fastq/SRR544/SRR544879/SRR544879_2.fastq.gz\tabc;def\t\t\n" +
+		"ERR3324530\t\t\tftp.sra.ebi.ac.uk/vol1/run/ERR332/ERR3324530/a.bam\t123\n"
+	records, err := fetch.ParseEnaReport(strings.NewReader(report))
+	if err != nil || len(records) != 2 {
+		t.Fatalf("unexpected records: %v %v", records, err)
+	}
+	files := enaRunFiles(records[0])
+	if len(files) != 2 || files[1].URL != "https://ftp.sra.ebi.ac.uk/vol1/fastq/SRR544/SRR544879/SRR544879_2.fastq.gz" ||
+		files[1].MD5 != "def" || files[1].Run != "SRR544879" {
+		t.Errorf("unexpected files: %+v", files)
+	}
+	files = enaRunFiles(records[1])
+	if len(files) != 1 || files[0].URL != "https://ftp.sra.ebi.ac.uk/vol1/run/ERR332/ERR3324530/a.bam" || files[0].MD5 != "123" {
+		t.Errorf("unexpected submitted files: %+v", files)
+	}
+}
+
+func TestCheckEnaFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bget-ena")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	downloadDir := bgetClis.DownloadDir
+	bgetClis.DownloadDir = dir
+	defer func() {
+		bgetClis.DownloadDir = downloadDir
+	}()
+	files := []enaFile{
+		{Run: "SRR1", URL: "https://ftp.sra.ebi.ac.uk/SRR1_1.fastq.gz", MD5: "e5059ed5026279463cb3e6ad8bce7e9d"},
+		{Run: "SRR1", URL: "https://ftp.sra.ebi.ac.uk/SRR1_2.fastq.gz", MD5: "c6b3e1a3f35b7a1c1b2fa1bcdf3ba3d9"},
+		{Run: "SRR2", URL: "https://ftp.sra.ebi.ac.uk/SRR2.fastq.gz"},
+		{Run: "SRR3", URL: "https://ftp.sra.ebi.ac.uk/SRR3.bam"},
+		{Run: "SRR4", URL: "https://ftp.sra.ebi.ac.uk/SRR4.bam"},
+	}
+	for _, f := range files[:4] {
+		os.MkdirAll(path.Dir(f.dest()), 0755)
+		ioutil.WriteFile(f.dest(), []byte("bget\n"), 0644)
+	}
+	ioutil.WriteFile(files[3].dest()+".st", []byte{}, 0644)
+	runs := enaFileRuns(checkEnaFiles(files))
+	if strings.Join(runs, ",") != "SRR1,SRR3,SRR4" {
+		t.Errorf("unexpected failed runs: %v", runs)
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// insdcRunRe match the run accessions of SRA, ENA and DDBJ
+var insdcRunRe = regexp.MustCompile(`^[SED]RR[0-9]+$`)
+
+// insdcExpandRe match the study, project, sample, experiment and
+// submission accessions that are expanded to runs
+var insdcExpandRe = regexp.MustCompile(`^(PRJ[EDN][A-Z][0-9]+|[SED]R[APSX][0-9]+|SAM[EDN][A-Z]?[0-9]+)$`)
+
+// runTableColumns is the runInfo columns of the printed run table
+var runTableColumns = []string{"run", "experiment", "sample", "study", "layout", "instrument", "read_count", "base_count"}
+
+func isInsdcRun(acc string) bool {
+	return insdcRunRe.MatchString(strings.ToUpper(strings.TrimSpace(acc)))
+}
+
+func isInsdcExpandable(acc string) bool {
+	return insdcExpandRe.MatchString(strings.ToUpper(strings.TrimSpace(acc)))
+}
+
+// expandSeqAccessions resolve the study, project, sample and experiment
+// accessions to their runs via ENA filereport
+func expandSeqAccessions(accessions []string) (runs []string, infos []runInfo) {
+	expanded := queryEnaRunInfo(accessions)
+	for _, acc := range accessions {
+		if len(expanded[acc]) == 0 {
+			log.Warnf("No runs found for %s.", acc)
+			continue
+		}
+		log.Infof("Expanding %s => %d runs.", acc, len(expanded[acc]))
+		infos = append(infos, expanded[acc]...)
+	}
+	infos = dropDupRunInfo(infos)
+	for _, v := range infos {
+		runs = append(runs, v["run"])
+	}
+	return runs, infos
+}
+
+// printRunTable print the runs of expanded accessions
+func printRunTable(infos []runInfo) {
+	if len(infos) == 0 {
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(runTableColumns)
+	table.SetAutoWrapText(false)
+	for _, v := range infos {
+		row := []string{}
+		for _, k := range runTableColumns {
+			row = append(row, v[k])
+		}
+		table.Append(row)
+	}
+	table.Render()
+}
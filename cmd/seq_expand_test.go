@@ -0,0 +1,21 @@
+package cmd
+
+import "testing"
+
+func TestParseSeqInsdc(t *testing.T) {
+	seqs, sep := bgetClis.Seqs, bgetClis.Seperator
+	defer func() {
+		bgetClis.Seqs, bgetClis.Seperator = seqs, sep
+	}()
+	bgetClis.Seqs, bgetClis.Seperator = "SRR544879,drr000001,PRJNA257197,SRP045416,SRX2676910,SAMN05201591,SAMEA104025456,ERS123456,GSE23543,b7670817-9d6b-494e-9e22-8494e2fd430d", ","
+	ret := parseSeq()
+	if len(ret["sra"]) != 2 || ret["sra"][1] != "DRR000001" {
+		t.Errorf("unexpected runs: %v", ret["sra"])
+	}
+	if len(ret["insdc"]) != 6 {
+		t.Errorf("unexpected expandable accessions: %v", ret["insdc"])
+	}
+	if len(ret["geo"]) != 1 || len(ret["tcgaFileID"]) != 1 {
+		t.Errorf("unexpected accessions: %v", ret)
+	}
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+var runFilterArgs []string
+var maxBases string
+var runLayout string
+var geoRuns bool
+var seqDryRun bool
+
+// runFilter is a key=value filter of --filter, Key is a runinfo.tsv column
+// or a sample attribute, e.g. library_strategy=RNA-Seq or sex=female
+type runFilter struct {
+	Key   string
+	Value string
+}
+
+func parseRunFilters(args []string) (filters []runFilter, err error) {
+	for _, v := range args {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return filters, fmt.Errorf("invalid filter %s (key=value)", v)
+		}
+		filters = append(filters, runFilter{Key: strings.ToLower(strings.TrimSpace(kv[0])), Value: strings.TrimSpace(kv[1])})
+	}
+	return filters, nil
+}
+
+// parseBases parse the number of bases with K, M, G or T suffix, e.g. 5G
+func parseBases(s string) (int64, error) {
+	s = strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B")
+	unit := 1.0
+	for i, v := range []string{"K", "M", "G", "T"} {
+		if strings.HasSuffix(s, v) {
+			unit = math.Pow(1000, float64(i+1))
+			s = strings.TrimSuffix(s, v)
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number of bases %s", s)
+	}
+	return int64(n * unit), nil
+}
+
+// value return the column or sample attribute of run
+func (r runInfo) value(key string) (string, bool) {
+	for _, k := range runInfoColumns {
+		if k == key {
+			return r[k], true
+		}
+	}
+	for _, v := range strings.Split(r["attributes"], ";") {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) == 2 && strings.EqualFold(kv[0], key) {
+			return kv[1], true
+		}
+	}
+	return "", false
+}
+
+// runSkipReason return why the run is skipped by the filters, empty if selected
+func runSkipReason(r runInfo, filters []runFilter, maxBases int64, layout string) string {
+	for _, f := range filters {
+		v, ok := r.value(f.Key)
+		if !ok {
+			return fmt.Sprintf("%s is missing", f.Key)
+		} else if !strings.EqualFold(v, f.Value) {
+			return fmt.Sprintf("%s=%s", f.Key, v)
+		}
+	}
+	if layout != "" && !strings.EqualFold(r["layout"], layout) {
+		return fmt.Sprintf("layout=%s", r["layout"])
+	}
+	if maxBases > 0 {
+		bases, err := strconv.ParseInt(r["base_count"], 10, 64)
+		if err != nil {
+			return "base_count is missing"
+		} else if bases > maxBases {
+			return fmt.Sprintf("base_count=%d > %d", bases, maxBases)
+		}
+	}
+	return ""
+}
+
+func runFiltersEnabled() bool {
+	return len(runFilterArgs) > 0 || maxBases != "" || runLayout != ""
+}
+
+// filterRunInfo apply --filter, --max-bases and --layout to the runs,
+// reasons maps the skipped runs to why they are skipped
+func filterRunInfo(infos []runInfo) (selected []runInfo, reasons map[string]string) {
+	reasons = make(map[string]string)
+	filters, err := parseRunFilters(runFilterArgs)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var max int64
+	if maxBases != "" {
+		if max, err = parseBases(maxBases); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	for _, v := range infos {
+		if reason := runSkipReason(v, filters, max, runLayout); reason != "" {
+			reasons[v["run"]] = reason
+			continue
+		}
+		selected = append(selected, v)
+	}
+	log.Infof("Selecting %d of %d runs.", len(selected), len(infos))
+	return selected, reasons
+}
+
+// printRunSelection print the selected and skipped runs of --dry-run
+func printRunSelection(infos []runInfo, reasons map[string]string) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Run", "Sample", "Strategy", "Layout", "Bases", "Selected", "Reason"})
+	table.SetAutoWrapText(false)
+	for _, v := range infos {
+		table.Append([]string{v["run"], v["sample"], v["library_strategy"], v["layout"], v["base_count"],
+			strconv.FormatBool(reasons[v["run"]] == ""), reasons[v["run"]]})
+	}
+	table.Render()
+}
+
+// requestedRuns return the runs to download: the SRA runs of seqs, and the
+// runs of GEO series in infos with --geo-runs
+func requestedRuns(runs []string, infos []runInfo) []string {
+	if !geoRuns {
+		return runs
+	}
+	seen := make(map[string]bool)
+	for _, v := range runs {
+		seen[v] = true
+	}
+	for _, v := range infos {
+		if !seen[v["run"]] {
+			seen[v["run"]] = true
+			runs = append(runs, v["run"])
+		}
+	}
+	return runs
+}
+
+// markUnrequestedRuns set the skip reason of runs in infos not to download
+func markUnrequestedRuns(runs []string, infos []runInfo, reasons map[string]string) {
+	requested := make(map[string]bool)
+	for _, v := range runs {
+		requested[v] = true
+	}
+	for _, v := range infos {
+		if !requested[v["run"]] && reasons[v["run"]] == "" {
+			reasons[v["run"]] = "run of GEO series (--geo-runs)"
+		}
+	}
+}
+
+// selectedRuns return the runs not skipped by the filters, runs without
+// metadata are skipped with a warning
+func selectedRuns(runs []string, infos []runInfo, reasons map[string]string) (selected []string) {
+	known := make(map[string]bool)
+	for _, v := range infos {
+		known[v["run"]] = true
+	}
+	for _, v := range runs {
+		if !known[v] {
+			log.Warnf("No metadata of %s, skipping it.", v)
+		} else if reasons[v] == "" {
+			selected = append(selected, v)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBases(t *testing.T) {
+	for s, want := range map[string]int64{"5G": 5000000000, "1.5m": 1500000, "200": 200, "2Gb": 2000000000} {
+		if n, err := parseBases(s); err != nil || n != want {
+			t.Errorf("unexpected bases of %s: %d %v", s, n, err)
+		}
+	}
+	if _, err := parseBases("five"); err == nil {
+		t.Error("invalid bases should fail")
+	}
+}
+
+func TestRunSkipReason(t *testing.T) {
+	run := runInfo{"run": "SRR1", "library_strategy": "RNA-Seq", "organism": "Homo sapiens",
+		"layout": "PAIRED", "base_count": "6000000000", "attributes": "sex=female;cell_type=T cell"}
+	filters, err := parseRunFilters([]string{"library_strategy=rna-seq", "organism=Homo sapiens", "sex=female"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason := runSkipReason(run, filters, 0, "paired"); reason != "" {
+		t.Errorf("run should be selected: %s", reason)
+	}
+	for _, c := range []struct {
+		filters []runFilter
+		max     int64
+		layout  string
+		want    string
+	}{
+		{[]runFilter{{"library_strategy", "ChIP-Seq"}}, 0, "", "library_strategy=RNA-Seq"},
+		{[]runFilter{{"tissue", "liver"}}, 0, "", "tissue is missing"},
+		{nil, 5000000000, "", "base_count=6000000000 > 5000000000"},
+		{nil, 0, "SINGLE", "layout=PAIRED"},
+	} {
+		if reason := runSkipReason(run, c.filters, c.max, c.layout); reason != c.want {
+			t.Errorf("unexpected reason: %s", reason)
+		}
+	}
+	if _, err := parseRunFilters([]string{"RNA-Seq"}); err == nil {
+		t.Error("invalid filter should fail")
+	}
+}
+
+func TestSelectedRuns(t *testing.T) {
+	infos := []runInfo{{"run": "SRR1"}, {"run": "SRR2"}, {"run": "SRR3"}}
+	reasons := map[string]string{"SRR2": "layout=SINGLE"}
+	defer func() { geoRuns = false }()
+	for _, c := range []struct {
+		geoRuns bool
+		want    string
+	}{
+		{false, "SRR1 SRR9"},
+		{true, "SRR1 SRR9 SRR2 SRR3"},
+	} {
+		geoRuns = c.geoRuns
+		if runs := requestedRuns([]string{"SRR1", "SRR9"}, infos); strings.Join(runs, " ") != c.want {
+			t.Errorf("unexpected requested runs with --geo-runs=%v: %v", c.geoRuns, runs)
+		}
+	}
+	// runs of GEO series are not added by the filters
+	if runs := selectedRuns([]string{"SRR1", "SRR2", "SRR9"}, infos, reasons); strings.Join(runs, " ") != "SRR1" {
+		t.Errorf("unexpected selected runs: %v", runs)
+	}
+	markUnrequestedRuns([]string{"SRR1"}, infos, reasons)
+	if reasons["SRR1"] != "" || reasons["SRR2"] != "layout=SINGLE" || reasons["SRR3"] == "" {
+		t.Errorf("unexpected reasons: %v", reasons)
+	}
+}
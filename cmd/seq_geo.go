@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/openanno/bget/api/fetch"
+	"github.com/openanno/bget/api/types"
+	cnet "github.com/openbiox/ligo/net"
+)
+
+var geoParse bool
+
+// geoSampleTable return the per-sample rows of GEO series with a column
+// per characteristic key, runs maps SRA experiments to runs
+func geoSampleTable(samples []types.GeoSample, runs map[string][]string) (lines []string) {
+	keys := []string{}
+	seen := make(map[string]bool)
+	for _, s := range samples {
+		for _, c := range s.Characteristics {
+			if !seen[c.Key] {
+				seen[c.Key] = true
+				keys = append(keys, c.Key)
+			}
+		}
+	}
+	header := append([]string{"gsm", "title", "source", "organism", "platform"}, keys...)
+	lines = append(lines, strings.Join(append(header, "srx", "srr"), "\t"))
+	for _, s := range samples {
+		values := make(map[string][]string)
+		for _, c := range s.Characteristics {
+			values[c.Key] = append(values[c.Key], c.Value)
+		}
+		row := []string{s.Accession, s.Title, s.Source, s.Organism, s.Platform}
+		for _, k := range keys {
+			row = append(row, strings.Join(values[k], ";"))
+		}
+		srr := []string{}
+		for _, srx := range s.SraExperiments() {
+			srr = append(srr, runs[srx]...)
+		}
+		row = append(row, strings.Join(s.SraExperiments(), ";"), strings.Join(srr, ";"))
+		for i := range row {
+			row[i] = strings.ReplaceAll(row[i], "\t", " ")
+		}
+		lines = append(lines, strings.Join(row, "\t"))
+	}
+	return lines
+}
+
+// geoRunMap map the SRA experiments of GEO series to runs via NCBI SRA
+func geoRunMap(gse string) map[string][]string {
+	runs := make(map[string][]string)
+	for _, v := range querySraRunInfo(gse) {
+		runs[v["experiment"]] = append(runs[v["experiment"]], v["run"])
+	}
+	return runs
+}
+
+// writeGeoRuns write the GSM, SRX and SRR of each run
+func writeGeoRuns(outfn string, samples []types.GeoSample, runs map[string][]string) error {
+	lines := []string{"gsm\tsrx\tsrr"}
+	for _, s := range samples {
+		for _, srx := range s.SraExperiments() {
+			for _, srr := range runs[srx] {
+				lines = append(lines, strings.Join([]string{s.Accession, srx, srr}, "\t"))
+			}
+		}
+	}
+	return ioutil.WriteFile(outfn, []byte(strings.Join(lines, "\n")+"\n"), 0664)
+}
+
+// convertSeriesMatrix write the data table of gzipped series matrix to outfn
+func convertSeriesMatrix(fn string, outfn string) (int, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+	of, err := os.Create(outfn)
+	if err != nil {
+		return 0, err
+	}
+	defer of.Close()
+	return fetch.ParseGeoSeriesMatrix(gz, of)
+}
+
+// parseGeoSeries parse the SOFT and series matrix of GEO series into
+// {gse}.samples.tsv, {gse}.runs.tsv and {matrix}.expression.tsv
+func parseGeoSeries(gse string, netOpt *cnet.Params) {
+	gse = strings.ToUpper(gse)
+	if !strings.HasPrefix(gse, "GSE") {
+		log.Warnf("--geo-parse only supports GEO series, skipping %s.", gse)
+		return
+	}
+	bapiClis := setBapiClis()
+	samples, err := fetch.GeoSoft(gse, bapiClis)
+	if err != nil {
+		log.Warnf("SOFT of %s: %v", gse, err)
+		return
+	}
+	runs := geoRunMap(gse)
+	outfn := path.Join(bgetClis.DownloadDir, gse+".samples.tsv")
+	if err = ioutil.WriteFile(outfn, []byte(strings.Join(geoSampleTable(samples, runs), "\n")+"\n"), 0664); err != nil {
+		log.Warnln(err)
+	} else {
+		log.Infof("Saving %d samples of %s => %s", len(samples), gse, outfn)
+	}
+	outfn = path.Join(bgetClis.DownloadDir, gse+".runs.tsv")
+	if err = writeGeoRuns(outfn, samples, runs); err != nil {
+		log.Warnln(err)
+	}
+	urls, err := fetch.GeoSeriesMatrixURLs(gse, bapiClis)
+	if err != nil {
+		log.Warnf("Series matrix of %s: %v", gse, err)
+		return
+	}
+	matrixDir := path.Join(bgetClis.DownloadDir, gse+"_matrix")
+	destDirs := []string{}
+	for range urls {
+		destDirs = append(destDirs, matrixDir)
+	}
+	cnet.HTTPGetURLs(urls, destDirs, netOpt)
+	for _, v := range urls {
+		fn := path.Join(matrixDir, path.Base(v))
+		outfn = path.Join(bgetClis.DownloadDir, strings.TrimSuffix(path.Base(v), "_series_matrix.txt.gz")+".expression.tsv")
+		n, err := convertSeriesMatrix(fn, outfn)
+		if err != nil {
+			log.Warnf("Series matrix %s: %v", fn, err)
+			continue
+		}
+		log.Infof("Saving expression matrix (%d rows) => %s", n-1, outfn)
+	}
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/openanno/bget/api/types"
+)
+
+func TestGeoSampleTable(t *testing.T) {
+	samples := []types.GeoSample{
+		{Accession: "GSM1", Title: "a", Characteristics: []types.GeoCharacteristic{{Key: "tissue", Value: "liver"}},
+			Relations: []string{"SRA: https://www.ncbi.nlm.nih.gov/sra?term=SRX1"}},
+		{Accession: "GSM2", Title: "b", Characteristics: []types.GeoCharacteristic{{Key: "age", Value: "42"}}},
+	}
+	lines := geoSampleTable(samples, map[string][]string{"SRX1": {"SRR1", "SRR2"}})
+	want := []string{
+		"gsm\ttitle\tsource\torganism\tplatform\ttissue\tage\tsrx\tsrr",
+		"GSM1\ta\t\t\t\tliver\t\tSRX1\tSRR1;SRR2",
+		"GSM2\tb\t\t\t\t\t42\t\t",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("unexpected lines: %q", lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("unexpected line %d: %q", i, lines[i])
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/openanno/bget/api/fetch"
+	"github.com/openanno/bget/api/types"
+)
+
+var metadataOnly bool
+var sampleSheet string
+
+// runInfo is a row of runinfo.tsv by runInfoColumns
+type runInfo map[string]string
+
+// runInfoColumns is the columns of runinfo.tsv shared by SRA, ENA and GEO
+var runInfoColumns = []string{"run", "experiment", "sample", "biosample", "study", "attributes", "organism",
+	"library_strategy", "library_source", "layout", "platform", "instrument", "read_count", "base_count", "fastq", "md5"}
+
+// enaRunInfoFields is the filereport fields converted to runInfo
+var enaRunInfoFields = []string{"run_accession", "experiment_accession", "secondary_sample_accession",
+	"sample_accession", "secondary_study_accession", "study_accession", "library_strategy", "library_source",
+	"library_layout", "instrument_platform", "instrument_model", "read_count", "base_count", "fastq_ftp", "fastq_md5",
+	"sample_title", "scientific_name", "cell_line", "cell_type", "tissue_type", "sex", "strain"}
+
+// runAttributeFields is the sample attributes of ENA kept in the attributes
+// column, the full BioSample attributes are not fetched
+var runAttributeFields = []string{"sample_title", "cell_line", "cell_type", "tissue_type", "sex", "strain"}
+
+// sraRunAttributeFields is the sample attributes of SRA runinfo kept in the
+// attributes column
+var sraRunAttributeFields = []string{"SampleName", "Sex", "Disease", "Tumor", "Body_Site"}
+
+var fastqMateRe = regexp.MustCompile(`_([12])[.](fastq|fq)([.]gz)?$`)
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func newEnaRunInfo(r types.EnaRecord) runInfo {
+	attrs := []string{}
+	for _, k := range runAttributeFields {
+		if r[k] != "" {
+			attrs = append(attrs, k+"="+r[k])
+		}
+	}
+	fastq := []string{}
+	for _, v := range r.List("fastq_ftp") {
+		fastq = append(fastq, enaURL(v))
+	}
+	return runInfo{
+		"run":              r["run_accession"],
+		"experiment":       r["experiment_accession"],
+		"sample":           firstNonEmpty(r["secondary_sample_accession"], r["sample_accession"]),
+		"biosample":        r["sample_accession"],
+		"study":            firstNonEmpty(r["secondary_study_accession"], r["study_accession"]),
+		"attributes":       strings.Join(attrs, ";"),
+		"organism":         r["scientific_name"],
+		"library_strategy": r["library_strategy"],
+		"library_source":   r["library_source"],
+		"layout":           r["library_layout"],
+		"platform":         r["instrument_platform"],
+		"instrument":       r["instrument_model"],
+		"read_count":       r["read_count"],
+		"base_count":       r["base_count"],
+		"fastq":            strings.Join(fastq, ";"),
+		"md5":              r["fastq_md5"],
+	}
+}
+
+func newSraRunInfo(r map[string]string) runInfo {
+	attrs := []string{}
+	for _, k := range sraRunAttributeFields {
+		if r[k] != "" {
+			attrs = append(attrs, k+"="+r[k])
+		}
+	}
+	return runInfo{
+		"run":              r["Run"],
+		"experiment":       r["Experiment"],
+		"sample":           r["Sample"],
+		"biosample":        r["BioSample"],
+		"study":            r["SRAStudy"],
+		"attributes":       strings.Join(attrs, ";"),
+		"organism":         r["ScientificName"],
+		"library_strategy": r["LibraryStrategy"],
+		"library_source":   r["LibrarySource"],
+		"layout":           r["LibraryLayout"],
+		"platform":         r["Platform"],
+		"instrument":       r["Model"],
+		"read_count":       r["spots"],
+		"base_count":       r["bases"],
+	}
+}
+
+// queryEnaRunInfo query the runs of accessions via ENA filereport
+func queryEnaRunInfo(accessions []string) map[string][]runInfo {
+	var lock sync.Mutex
+	sem := make(chan bool, bgetClis.Thread)
+	bapiClis := setBapiClis()
+	infos := make(map[string][]runInfo)
+	for _, acc := range accessions {
+		sem <- true
+		go func(acc string) {
+			defer func() {
+				<-sem
+			}()
+			records, err := fetch.EnaFileReport(acc, enaRunInfoFields, bapiClis)
+			if err != nil {
+				log.Warnf("ENA filereport of %s: %v", acc, err)
+			}
+			ret := []runInfo{}
+			for _, r := range records {
+				ret = append(ret, newEnaRunInfo(r))
+			}
+			lock.Lock()
+			infos[acc] = ret
+			lock.Unlock()
+		}(acc)
+	}
+	for i := 0; i < cap(sem); i++ {
+		sem <- true
+	}
+	return infos
+}
+
+// querySraRunInfo query the runs of term via NCBI SRA runinfo
+func querySraRunInfo(term string) (infos []runInfo) {
+	records, err := fetch.SraRunInfo(term, setBapiClis())
+	if err != nil {
+		log.Warnf("SRA runinfo of %s: %v", term, err)
+	}
+	for _, r := range records {
+		infos = append(infos, newSraRunInfo(r))
+	}
+	return infos
+}
+
+// geoRunInfo query the runs of GEO series or samples via NCBI SRA, the
+// rows are completed by ENA (FASTQ and md5) if available
+func geoRunInfo(geo string) (infos []runInfo) {
+	sraInfos := querySraRunInfo(geo)
+	studies := []string{}
+	seen := make(map[string]bool)
+	for _, v := range sraInfos {
+		if v["study"] != "" && !seen[v["study"]] {
+			seen[v["study"]] = true
+			studies = append(studies, v["study"])
+		}
+	}
+	enaInfos := make(map[string]runInfo)
+	for _, rows := range queryEnaRunInfo(studies) {
+		for _, v := range rows {
+			enaInfos[v["run"]] = v
+		}
+	}
+	for _, v := range sraInfos {
+		if ena, ok := enaInfos[v["run"]]; ok {
+			v = ena
+		}
+		infos = append(infos, v)
+	}
+	return infos
+}
+
+// collectRunInfo query the runs of SRA/ENA runs and GEO IDs that are not
+// in known, runs missing in ENA are queried via NCBI SRA
+func collectRunInfo(seqs map[string][]string, known []runInfo) (infos []runInfo) {
+	infos = append(infos, known...)
+	seen := make(map[string]bool)
+	for _, v := range known {
+		seen[v["run"]] = true
+	}
+	runs := []string{}
+	for _, v := range seqs["sra"] {
+		if !seen[v] {
+			runs = append(runs, v)
+		}
+	}
+	enaInfos := queryEnaRunInfo(runs)
+	missing := []string{}
+	for _, run := range runs {
+		if len(enaInfos[run]) == 0 {
+			missing = append(missing, run)
+		}
+		infos = append(infos, enaInfos[run]...)
+	}
+	if len(missing) > 0 {
+		infos = append(infos, querySraRunInfo(strings.Join(missing, " OR "))...)
+	}
+	for _, geo := range seqs["geo"] {
+		infos = append(infos, geoRunInfo(geo)...)
+	}
+	return dropDupRunInfo(infos)
+}
+
+func dropDupRunInfo(infos []runInfo) (ret []runInfo) {
+	seen := make(map[string]bool)
+	for _, v := range infos {
+		if v["run"] == "" || seen[v["run"]] {
+			continue
+		}
+		seen[v["run"]] = true
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+func writeRunInfo(infos []runInfo) {
+	lines := []string{strings.Join(runInfoColumns, "\t")}
+	for _, v := range infos {
+		row := []string{}
+		for _, k := range runInfoColumns {
+			row = append(row, strings.ReplaceAll(v[k], "\t", " "))
+		}
+		lines = append(lines, strings.Join(row, "\t"))
+	}
+	outfn := path.Join(bgetClis.DownloadDir, "runinfo.tsv")
+	if err := ioutil.WriteFile(outfn, []byte(strings.Join(lines, "\n")+"\n"), 0664); err != nil {
+		log.Warnln(err)
+		return
+	}
+	log.Infof("Saving run info of %d runs => %s", len(infos), outfn)
+}
+
+// pairFastq return the R1 and R2 (empty for single-end runs) of FASTQ files
+func pairFastq(files []string) (r1 string, r2 string) {
+	single := ""
+	for _, v := range files {
+		if m := fastqMateRe.FindStringSubmatch(v); m == nil {
+			single = v
+		} else if m[1] == "1" {
+			r1 = v
+		} else {
+			r2 = v
+		}
+	}
+	if r1 == "" {
+		return single, ""
+	}
+	return r1, r2
+}
+
+// fastqPath return the local path of the FASTQ downloaded by --source ena,
+// otherwise the URL
+func fastqPath(run string, link string) string {
+	if metadataOnly || seqSource != "ena" || link == "" {
+		return link
+	}
+	return enaFile{Run: run, URL: link}.dest()
+}
+
+// writeSampleSheet write the nf-core/rnaseq samplesheet.csv, the runs of
+// the same sample are merged by the pipeline
+func writeSampleSheet(infos []runInfo) {
+	switch sampleSheet {
+	case "nf-core/rnaseq":
+	default:
+		log.Fatalf("Unsupported samplesheet %s (nf-core/rnaseq).", sampleSheet)
+	}
+	lines := []string{"sample,fastq_1,fastq_2,strandedness"}
+	for _, v := range infos {
+		r1, r2 := pairFastq(strings.Split(v["fastq"], ";"))
+		if r1 == "" {
+			log.Warnf("No FASTQ of %s in ENA, skipping it in the samplesheet.", v["run"])
+			continue
+		}
+		sample := firstNonEmpty(v["sample"], v["experiment"], v["run"])
+		lines = append(lines, strings.Join([]string{sample, fastqPath(v["run"], r1), fastqPath(v["run"], r2), "auto"}, ","))
+	}
+	outfn := path.Join(bgetClis.DownloadDir, "samplesheet.csv")
+	if err := ioutil.WriteFile(outfn, []byte(strings.Join(lines, "\n")+"\n"), 0664); err != nil {
+		log.Warnln(err)
+		return
+	}
+	log.Infof("Saving %s samplesheet => %s", sampleSheet, outfn)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openanno/bget/api/fetch"
+	"github.com/openanno/bget/api/types"
+)
+
+func TestPairFastq(t *testing.T) {
+	for _, c := range []struct {
+		files  []string
+		r1, r2 string
+	}{
+		{[]string{"a/SRR1_1.fastq.gz", "a/SRR1_2.fastq.gz"}, "a/SRR1_1.fastq.gz", "a/SRR1_2.fastq.gz"},
+		{[]string{"a/SRR1.fastq.gz", "a/SRR1_1.fastq.gz", "a/SRR1_2.fastq.gz"}, "a/SRR1_1.fastq.gz", "a/SRR1_2.fastq.gz"},
+		{[]string{"a/SRR1.fastq.gz"}, "a/SRR1.fastq.gz", ""},
+		{[]string{""}, "", ""},
+	} {
+		if r1, r2 := pairFastq(c.files); r1 != c.r1 || r2 != c.r2 {
+			t.Errorf("unexpected pair of %v: %s %s", c.files, r1, r2)
+		}
+	}
+}
+
+func TestNewRunInfo(t *testing.T) {
+	enaProtocol = "https"
+	defer func() {
+		enaProtocol = ""
+	}()
+	info := newEnaRunInfo(types.EnaRecord{"run_accession": "SRR1", "secondary_sample_accession": "SRS1",
+		"sample_accession": "SAMN1", "library_layout": "PAIRED", "sex": "female",
+		"fastq_ftp": "ftp.sra.ebi.ac.uk/SRR1_1.fastq.gz;ftp.sra.ebi.ac.uk/SRR1_2.fastq.gz"})
+	if info["sample"] != "SRS1" || info["biosample"] != "SAMN1" || info["attributes"] != "sex=female" ||
+		info["fastq"] != "https://ftp.sra.ebi.ac.uk/SRR1_1.fastq.gz;https://ftp.sra.ebi.ac.uk/SRR1_2.fastq.gz" {
+		t.Errorf("unexpected ENA run info: %v", info)
+	}
+	csv := "Run,Experiment,Sample,BioSample,LibraryLayout,spots,bases\nSRR2,SRX2,SRS2,SAMN2,SINGLE,10,1000\n\n" +
+		"Run,Experiment,Sample,BioSample,LibraryLayout,spots,bases\nSRR3,SRX3,SRS3,SAMN3,PAIRED,20,4000\n"
+	records, err := fetch.ParseSraRunInfo(strings.NewReader(csv))
+	if err != nil || len(records) != 2 {
+		t.Fatalf("unexpected records: %v %v", records, err)
+	}
+	if info = newSraRunInfo(records[1]); info["run"] != "SRR3" || info["layout"] != "PAIRED" || info["base_count"] != "4000" {
+		t.Errorf("unexpected SRA run info: %v", info)
+	}
+}
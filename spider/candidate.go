@@ -0,0 +1,105 @@
+package spider
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/openbiox/ligo/stringo"
+)
+
+// Kinds of DoiCandidate
+const (
+	CandidateFullText = "fulltext"
+	CandidateSuppl    = "suppl"
+	CandidateVersion  = "version"
+	CandidateDataset  = "dataset"
+	CandidateOther    = "other"
+)
+
+// CandidateSourceScores is the confidence of candidate sources
+var CandidateSourceScores = map[string]float64{
+	"citation_pdf_url": 1.0,
+	"preprint":         0.9,
+	"pmc":              0.85,
+	"publisher":        0.8,
+	"chrome":           0.7,
+	"universal":        0.5,
+	"scihub":           0.3,
+}
+
+// DoiCandidate is a candidate URL of DOI with its source and score
+type DoiCandidate struct {
+	URL       string  `json:"url"`
+	Source    string  `json:"source"`
+	Kind      string  `json:"kind"`
+	Score     float64 `json:"score"`
+	Validated bool    `json:"validated"`
+	Selected  bool    `json:"selected"`
+	Label     string  `json:"label,omitempty"`
+	Caption   string  `json:"caption,omitempty"`
+}
+
+// ScoreCandidates attach source, kind and score to the URLs returned by a spider,
+// URLs equal to the citation_pdf_url meta are preferred
+func ScoreCandidates(urls []string, source string, opt *DoiSpiderOpt) (candidates []DoiCandidate) {
+	citationPdf := ""
+	if opt != nil && opt.CitationMeta != nil {
+		citationPdf = strings.Split((*opt.CitationMeta)["citation_pdf_url"], "; ")[0]
+	}
+	supplRequired := opt != nil && opt.Supplementary
+	for _, v := range urls {
+		c := DoiCandidate{URL: v, Source: source}
+		if citationPdf != "" && v == citationPdf {
+			c.Source = "citation_pdf_url"
+		}
+		var bonus float64
+		c.Kind, bonus = classifyCandidate(v, supplRequired && (source == "publisher" || source == "universal"))
+		c.Score = CandidateSourceScores[c.Source] + bonus
+		candidates = append(candidates, c)
+	}
+	return candidates
+}
+
+// classifyCandidate guess the kind of candidate from URL, links without
+// a full text hint are supplementary files if supplPage is true
+func classifyCandidate(link string, supplPage bool) (kind string, bonus float64) {
+	lower := strings.ToLower(link)
+	ext := strings.TrimPrefix(path.Ext(strings.Split(lower, "?")[0]), ".")
+	switch {
+	case stringo.StrDetect(lower, `suppl|moesm|_esm[.]|/esm/|mmc[0-9]+|media-[0-9]+|datasheet|/attachment|figure[ _-]?s[0-9]|table[ _-]?s[0-9]|[?&]file=`):
+		return CandidateSuppl, 0
+	case stringo.StrDetect(ext, `^(xlsx?|docx?|pptx?|zip|gz|tar|rar|7z|csv|tsv|txt|mp4|avi|mov|tiff?|jpe?g|png|r|py|fa|fasta|fastq|bam|vcf|xml)$`):
+		return CandidateSuppl, 0
+	case ext == "epub" || stringo.StrDetect(lower, `/epub/|/figures?/|/fig[0-9]+|related|/toc/|/cover`):
+		return CandidateOther, -0.5
+	case ext == "pdf" || stringo.StrDetect(lower, `/pdf|pdf/|pdfft|pdfdirect|pdfplus|article-pdf|/pdf[?]|download=pdf|[.]full[.]pdf`):
+		return CandidateFullText, 0.05
+	case supplPage:
+		return CandidateSuppl, 0
+	}
+	return CandidateFullText, -0.1
+}
+
+// RankCandidates remove duplicated URLs (keeping the highest score) and
+// sort the candidates by score
+func RankCandidates(candidates []DoiCandidate) (ranked []DoiCandidate) {
+	idx := make(map[string]int)
+	for _, c := range candidates {
+		if i, ok := idx[c.URL]; ok {
+			if c.Score > ranked[i].Score {
+				c.Validated = c.Validated || ranked[i].Validated
+				ranked[i] = c
+			} else {
+				ranked[i].Validated = ranked[i].Validated || c.Validated
+			}
+			continue
+		}
+		idx[c.URL] = len(ranked)
+		ranked = append(ranked, c)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
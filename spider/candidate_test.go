@@ -0,0 +1,28 @@
+package spider
+
+import "testing"
+
+func TestScoreCandidates(t *testing.T) {
+	meta := map[string]string{"citation_pdf_url": "https://www.nature.com/articles/s41586-019-1844-5.pdf"}
+	opt := &DoiSpiderOpt{CitationMeta: &meta, Supplementary: true}
+	urls := []string{
+		"https://www.nature.com/articles/s41586-019-1844-5.epub",
+		"https://www.nature.com/articles/s41586-019-1844-5/figures/1.pdf",
+		"https://static-content.springer.com/esm/art%3A10.1038%2Fs41586-019-1844-5/MediaObjects/41586_2019_1844_MOESM1_ESM.pdf",
+		"https://www.nature.com/articles/s41586-019-1844-5.pdf",
+		"https://www.nature.com/articles/s41586-019-1844-5/tables/data.xlsx",
+	}
+	ranked := RankCandidates(ScoreCandidates(urls, "publisher", opt))
+	if ranked[0].URL != meta["citation_pdf_url"] || ranked[0].Source != "citation_pdf_url" || ranked[0].Kind != CandidateFullText {
+		t.Errorf("unexpected best candidate: %+v", ranked[0])
+	}
+	kinds := map[string]string{}
+	for _, c := range ranked {
+		kinds[c.URL] = c.Kind
+	}
+	for i, kind := range []string{CandidateOther, CandidateOther, CandidateSuppl, CandidateFullText, CandidateSuppl} {
+		if kinds[urls[i]] != kind {
+			t.Errorf("unexpected kind of %s: %s", urls[i], kinds[urls[i]])
+		}
+	}
+}
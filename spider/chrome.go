@@ -0,0 +1,48 @@
+package spider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openanno/bget/chrome"
+)
+
+// ChromeSpider render the landing page of DOI with the headless browser,
+// used as the fallback of JavaScript-rendered pages
+func ChromeSpider(opt *DoiSpiderOpt) (urls []string) {
+	if !chrome.Enabled() {
+		return urls
+	}
+	link := fmt.Sprintf("https://doi.org/%s", opt.Doi)
+	if opt.URL != nil {
+		link = opt.URL.String()
+	}
+	timeout := time.Duration(opt.Timeout) * time.Second
+	links, meta, err := chrome.PageLinks(link, timeout, opt.Proxy)
+	if err != nil {
+		log.Warnf("Chrome spider of %s: %v", opt.Doi, err)
+	}
+	for k, v := range meta {
+		if opt.CitationMeta != nil && v != "" && (*opt.CitationMeta)[k] == "" {
+			(*opt.CitationMeta)[k] = v
+		}
+	}
+	for _, v := range links {
+		kind, bonus := classifyCandidate(v, false)
+		if opt.FullText && kind == CandidateFullText && bonus > 0 {
+			urls = append(urls, v)
+		} else if opt.Supplementary && kind == CandidateSuppl {
+			urls = append(urls, v)
+		}
+	}
+	if opt.Supplementary {
+		suppls, err := chrome.DoiSupplURLs(link, timeout, opt.Proxy)
+		if err != nil {
+			log.Warnf("Chrome spider of %s: %v", opt.Doi, err)
+		}
+		urls = append(urls, suppls...)
+	}
+	addCitationPdfURL(opt, &urls)
+	postSpiderPrint(opt, &urls)
+	return urls
+}
@@ -9,9 +9,9 @@ import (
 	"time"
 
 	"github.com/gocolly/colly"
+	"github.com/openanno/bget/cassette"
 	"github.com/openanno/bget/chrome"
 	glog "github.com/openbiox/ligo/log"
-	cnet "github.com/openbiox/ligo/net"
 	"github.com/openbiox/ligo/stringo"
 )
 
@@ -22,13 +22,13 @@ func NatureComSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.nature.com", "idp.nature.com"}...)
 	if opt.FullText {
-		c.OnHTML("a.c-pdf-download__link[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.c-pdf-download__link[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("a.print-link[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.print-link[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if !strings.Contains(link, "/figures/") {
 				if !strings.HasPrefix(link, "http") {
@@ -60,14 +60,14 @@ func ScienseComSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, ScienseComJournalLinks...)
 	if opt.FullText {
-		c.OnHTML("div.panels-ajax-tab-wrap-jnl_sci_tab_pdf a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "div.panels-ajax-tab-wrap-jnl_sci_tab_pdf a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	Visit(c, fmt.Sprintf("https://doi.org/%s", opt.Doi))
 	if opt.Supplementary {
-		c.OnHTML("a.rewritten[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.rewritten[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -83,25 +83,25 @@ func CellComSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, CellComJournalLinks...)
 	if opt.FullText {
-		c.OnHTML("a.pdfLink[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.pdfLink[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if len(urls) == 0 {
 				urls = append(urls, link)
 			}
 		})
-		c.OnHTML("a.article-tools__item__displayStandardPdf[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.article-tools__item__displayStandardPdf[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if link != "#" && len(urls) == 0 {
 				urls = append(urls, linkFilter(link, opt.URL))
 			}
 		})
-		c.OnHTML("a.article-tools__item__displayExtendedPdf[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.article-tools__item__displayExtendedPdf[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if link != "#" && len(urls) == 0 {
 				urls = append(urls, linkFilter(link, opt.URL))
 			}
 		})
-		c.OnHTML(".article-tools__pdf a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".article-tools__pdf a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if link != "#" && len(urls) == 0 {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -109,39 +109,39 @@ func CellComSpider(opt *DoiSpiderOpt) (urls []string) {
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("#appsec1 a[target=new]", func(e *colly.HTMLElement) {
+		onHTML(c, "#appsec1 a[target=new]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, link)
 		})
-		c.OnHTML("#appsec1 .externalFile a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "#appsec1 .externalFile a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, link)
 		})
-		c.OnHTML("span.article-attachment a.download-link[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "span.article-attachment a.download-link[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, link)
 		})
-		c.OnHTML("a.supplemental-information__download[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.supplemental-information__download[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if link != "#" {
 				urls = append(urls, linkFilter(link, opt.URL))
 			}
 		})
-		c.OnHTML(".supplemental-information__links a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".supplemental-information__links a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if link != "#" {
 				urls = append(urls, linkFilter(link, opt.URL))
 			}
 		})
 	}
-	c.OnHTML("#redirectURL", func(e *colly.HTMLElement) {
+	onHTML(c, "#redirectURL", func(e *colly.HTMLElement) {
 		link := e.Attr("value")
 		u, _ := url.Parse(link)
 		link, _ = url.QueryUnescape(u.Path)
 		link = stringo.StrReplaceAll(link, "http://.*/retrieve/pii/", "https://www.sciencedirect.com/science/article/pii/")
 		Visit(c, link)
 	})
-	c.OnHTML("meta[HTTP-EQUIV=REFRESH]", func(e *colly.HTMLElement) {
+	onHTML(c, "meta[HTTP-EQUIV=REFRESH]", func(e *colly.HTMLElement) {
 		link := e.Attr("content")
 		link = stringo.StrReplaceAll(link, ".* url='", "")
 		link = stringo.StrReplaceAll(link, "'$", "")
@@ -150,7 +150,7 @@ func CellComSpider(opt *DoiSpiderOpt) (urls []string) {
 			Visit(c, link)
 		}
 	})
-	c.OnHTML("div.PdfDownloadButton a[href]", func(e *colly.HTMLElement) {
+	onHTML(c, "div.PdfDownloadButton a[href]", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		link = "https://www.sciencedirect.com" + link
 		if len(urls) == 0 && opt.FullText {
@@ -160,9 +160,13 @@ func CellComSpider(opt *DoiSpiderOpt) (urls []string) {
 	})
 	Visit(c, fmt.Sprintf("https://doi.org/%s", opt.Doi))
 	addCitationPdfURL(opt, &urls)
-	if opt.Supplementary && len(urls) > 0 {
+	if opt.Supplementary && len(urls) > 0 && chrome.Enabled() {
 		link := stringo.StrReplaceAll(urls[0], "/pdfft?.*", "")
-		urls = append(urls, chrome.DoiSupplURLs(link, time.Duration(opt.Timeout)*time.Second, opt.Proxy)...)
+		suppls, err := chrome.DoiSupplURLs(link, time.Duration(opt.Timeout)*time.Second, opt.Proxy)
+		if err != nil {
+			log.Warnf("Chrome spider of %s: %v", link, err)
+		}
+		urls = append(urls, suppls...)
 	}
 	postSpiderPrint(opt, &urls)
 	return urls
@@ -173,11 +177,11 @@ func BloodJournalSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"signin.hematology.org", "www.bloodjournal.org", "ashpublications.org"}...)
 	if opt.Supplementary {
-		c.OnHTML("a.[data-panel-name=jnl_bloodjournal_tab_data]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.[data-panel-name=jnl_bloodjournal_tab_data]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			Visit(c, link)
 		})
-		c.OnHTML("a.rewritten[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.rewritten[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			link = linkFilter(link, opt.URL)
 			urls = append(urls, link)
@@ -194,13 +198,13 @@ func NejmSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://www.nejm.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.nejm.org"}...)
 	if opt.FullText {
-		c.OnHTML("a[data-tooltip='Download PDF']", func(e *colly.HTMLElement) {
+		onHTML(c, "a[data-tooltip='Download PDF']", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("a[data-interactionType=multimedia_download]", func(e *colly.HTMLElement) {
+		onHTML(c, "a[data-interactionType=multimedia_download]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, "doi/suppl") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -221,7 +225,7 @@ func AhajournalsSpider(opt *DoiSpiderOpt) (urls []string) {
 		urls = append(urls, fmt.Sprintf("https://www.ahajournals.org/doi/pdf/%s?download=true", opt.Doi))
 	}
 	if opt.Supplementary {
-		c.OnHTML("li.supplemental-material__item a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "li.supplemental-material__item a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, link)
 		})
@@ -237,13 +241,13 @@ func JamaNetworkSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://jamanetwork.com")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"jamanetwork.com"}...)
 	if opt.FullText {
-		c.OnHTML("#contents-tab a.toolbar-pdf[data-article-url]", func(e *colly.HTMLElement) {
+		onHTML(c, "#contents-tab a.toolbar-pdf[data-article-url]", func(e *colly.HTMLElement) {
 			link := e.Attr("data-article-url")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML(".supplement a.supplement-download[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".supplement a.supplement-download[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, link)
 		})
@@ -259,7 +263,7 @@ func AacrJournalsSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, AacrJournalLinks...)
 	if opt.Supplementary {
-		c.OnHTML("a.rewritten[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.rewritten[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -286,7 +290,7 @@ func TandfonlineSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "www.tandfonline.com")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.tandfonline.com"}...)
 	if opt.FullText {
-		c.OnHTML("a[title='Download all']", func(e *colly.HTMLElement) {
+		onHTML(c, "a[title='Download all']", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -295,11 +299,11 @@ func TandfonlineSpider(opt *DoiSpiderOpt) (urls []string) {
 	}
 	Visit(c, fmt.Sprintf("https://doi.org/%s", opt.Doi))
 	if opt.Supplementary {
-		c.OnHTML("a.show-pdf[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.show-pdf[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
-		c.OnHTML("#supplementaryPanel a", func(e *colly.HTMLElement) {
+		onHTML(c, "#supplementaryPanel a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -315,7 +319,7 @@ func BmjComSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, BmjComJournalLinks...)
 	fulltextUrl := ""
-	c.OnHTML("a.pdf-link[href]", func(e *colly.HTMLElement) {
+	onHTML(c, "a.pdf-link[href]", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		fulltextUrl = "https://" + opt.URL.Hostname() + link
 		if opt.FullText {
@@ -325,7 +329,7 @@ func BmjComSpider(opt *DoiSpiderOpt) (urls []string) {
 			Visit(c, stringo.StrReplaceAll(fulltextUrl, ".full.pdf", "/related"))
 		}
 	})
-	c.OnHTML("a.article-pdf-download[href]", func(e *colly.HTMLElement) {
+	onHTML(c, "a.article-pdf-download[href]", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		fulltextUrl = "https://" + opt.URL.Hostname() + link
 		if opt.FullText {
@@ -333,10 +337,10 @@ func BmjComSpider(opt *DoiSpiderOpt) (urls []string) {
 		}
 	})
 	if opt.Supplementary {
-		c.OnHTML(".supplementary-material a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".supplementary-material a[href]", func(e *colly.HTMLElement) {
 			urls = append(urls, e.Attr("href"))
 		})
-		c.OnHTML("a.rewritten[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.rewritten[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			link = "https://" + opt.URL.Hostname() + link
 			urls = append(urls, link)
@@ -353,7 +357,7 @@ func JournalsApsSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://journals.aps.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"journals.aps.org", "link.aps.org"}...)
 	if opt.FullText {
-		c.OnHTML(".article-nav-actions a", func(e *colly.HTMLElement) {
+		onHTML(c, ".article-nav-actions a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, "/pdf/") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -370,7 +374,7 @@ func CellimageLibrarySpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://www.cellimagelibrary.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.cellimagelibrary.org", "cellimagelibrary.org"}...)
 	if opt.FullText {
-		c.OnHTML("a.download_menu_anchor", func(e *colly.HTMLElement) {
+		onHTML(c, "a.download_menu_anchor", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, ".zip") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -387,7 +391,7 @@ func IeeexploreSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://ieeexplore.ieee.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"ieeexplore.ieee.org"}...)
 	if opt.FullText {
-		c.OnHTML("a.download_menu_anchor", func(e *colly.HTMLElement) {
+		onHTML(c, "a.download_menu_anchor", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, ".zip") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -401,7 +405,7 @@ func IeeexploreSpider(opt *DoiSpiderOpt) (urls []string) {
 		}
 		done = true
 		link := fmt.Sprintf("/stamp/stamp.jsp?arnumber=%s", path.Base(r.Request.URL.String()))
-		c.OnHTML("iframe", func(e *colly.HTMLElement) {
+		onHTML(c, "iframe", func(e *colly.HTMLElement) {
 			link := e.Attr("src")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -417,7 +421,7 @@ func SagepubComSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"journals.sagepub.com"}...)
 	if opt.FullText {
-		c.OnHTML(".pdf-access a", func(e *colly.HTMLElement) {
+		onHTML(c, ".pdf-access a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, "/pdf/") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -438,15 +442,15 @@ func LwwComSpider(opt *DoiSpiderOpt) (urls []string) {
 	c.AllowedDomains = append(c.AllowedDomains, []string{"journals.lww.com",
 		"links.lww.com", "download.lww.com"}...)
 	if opt.FullText {
-		c.OnHTML("div.ejp-article-wrapper #js-ejp-article-tools", func(e *colly.HTMLElement) {
+		onHTML(c, "div.ejp-article-wrapper #js-ejp-article-tools", func(e *colly.HTMLElement) {
 			link := e.Attr("data-pdf-url")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("#ej-article-sam-container a", func(e *colly.HTMLElement) {
+		onHTML(c, "#ej-article-sam-container a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
-			client := cnet.NewHTTPClient(opt.Timeout, opt.Proxy)
+			client := cassette.NewHTTPClient(opt.Timeout, opt.Proxy)
 			req, _ := http.NewRequest("HEAD", link, nil)
 			resp, _ := client.Do(req)
 			urls = append(urls, resp.Request.URL.String())
@@ -465,7 +469,7 @@ func LiebertpubSpider(opt *DoiSpiderOpt) (urls []string) {
 		urls = append(urls, linkFilter("/doi/pdfplus/"+opt.Doi, opt.URL))
 	}
 	if opt.Supplementary {
-		c.OnHTML("a.ext-link", func(e *colly.HTMLElement) {
+		onHTML(c, "a.ext-link", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -507,7 +511,7 @@ func AmegroupsSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://tlcr.amegroups.com")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"tlcr.amegroups.com"}...)
 	if opt.FullText {
-		c.OnHTML("li a.pdf", func(e *colly.HTMLElement) {
+		onHTML(c, "li a.pdf", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			link = strings.ReplaceAll(link, "view", "download")
 			urls = append(urls, linkFilter(link, opt.URL))
@@ -555,7 +559,7 @@ func ThiemeConnectDeSpider(opt *DoiSpiderOpt) (urls []string) {
 		log.Infof("Visiting %s", r.URL.String())
 	})
 	if opt.FullText {
-		c.OnHTML("meta[name=citation_pdf_url]", func(e *colly.HTMLElement) {
+		onHTML(c, "meta[name=citation_pdf_url]", func(e *colly.HTMLElement) {
 			link := e.Attr("content")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -574,7 +578,7 @@ func ThnoOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.thno.org", "www.jcancer.org", "www.ntno.org",
 		"www.ijbs.com", "www.medsci.org", "www.jgenomics.com", "www.jbji.net"}...)
 	if opt.FullText {
-		c.OnHTML("a.textbutton", func(e *colly.HTMLElement) {
+		onHTML(c, "a.textbutton", func(e *colly.HTMLElement) {
 			link := "/" + e.Attr("href")
 			if strings.Contains(link, ".pdf") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -591,13 +595,13 @@ func GeochemicalperspectivesOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://www.geochemicalperspectives.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.geochemicalperspectives.org"}...)
 	if opt.FullText {
-		c.OnHTML(".entry-content p a", func(e *colly.HTMLElement) {
+		onHTML(c, ".entry-content p a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, ".pdf") {
 				urls = append(urls, linkFilter(link, opt.URL))
 			}
 		})
-		c.OnHTML("#GPLpdf tr td a:first-child", func(e *colly.HTMLElement) {
+		onHTML(c, "#GPLpdf tr td a:first-child", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, ".pdf") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -623,13 +627,13 @@ func IucrOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://journals.iucr.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"journals.iucr.org", "scripts.iucr.org"}...)
 	if opt.FullText {
-		c.OnHTML(".bubbleInfo .sidebutton a[title=PDF]", func(e *colly.HTMLElement) {
+		onHTML(c, ".bubbleInfo .sidebutton a[title=PDF]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML(".file_links_other p a", func(e *colly.HTMLElement) {
+		onHTML(c, ".file_links_other p a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -644,7 +648,7 @@ func GeoscienceworldOrg(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://pubs.geoscienceworld.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"pubs.geoscienceworld.org"}...)
 	if opt.FullText {
-		c.OnHTML(".article-pdfLink", func(e *colly.HTMLElement) {
+		onHTML(c, ".article-pdfLink", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, "https://pubs.geoscienceworld.org"+link)
 		})
@@ -659,13 +663,13 @@ func AeawebOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://www.aeaweb.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"pubs.aeaweb.org", "www.aeaweb.org"}...)
 	if opt.FullText {
-		c.OnHTML(".download a", func(e *colly.HTMLElement) {
+		onHTML(c, ".download a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("#additionalMaterials li a", func(e *colly.HTMLElement) {
+		onHTML(c, "#additionalMaterials li a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -681,7 +685,7 @@ func InformsOrgSPider(opt *DoiSpiderOpt) (urls []string) {
 	c.AllowedDomains = append(c.AllowedDomains, []string{"pubsonline.informs.org"}...)
 	urls = AddPdfSpider(opt)
 	if opt.Supplementary {
-		c.OnHTML(".article-section__content p a", func(e *colly.HTMLElement) {
+		onHTML(c, ".article-section__content p a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -697,13 +701,13 @@ func AsnjournalsOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://jasn.asnjournals.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"jasn.asnjournals.org", "www.jasn.org"}...)
 	if opt.FullText {
-		c.OnHTML("a[data-panel-name=jnl_asnjnls_tab_pdf]", func(e *colly.HTMLElement) {
+		onHTML(c, "a[data-panel-name=jnl_asnjnls_tab_pdf]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("a.rewritten", func(e *colly.HTMLElement) {
+		onHTML(c, "a.rewritten", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -728,7 +732,7 @@ func AdiccionesEsSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://www.adicciones.es")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.adicciones.es"}...)
 	if opt.FullText {
-		c.OnHTML("#articleFullText a:nth-child(3)", func(e *colly.HTMLElement) {
+		onHTML(c, "#articleFullText a:nth-child(3)", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			link = strings.ReplaceAll(link, "/view/", "/download/")
 			urls = append(urls, linkFilter(link, opt.URL))
@@ -744,7 +748,7 @@ func EurosurveillanceOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://www.eurosurveillance.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.eurosurveillance.org"}...)
 	if opt.FullText {
-		c.OnHTML(".pdfItem a.pdf", func(e *colly.HTMLElement) {
+		onHTML(c, ".pdfItem a.pdf", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if !strings.Contains(link, "suppdata") {
 				link, _ = RetriveRedirectLink(linkFilter(link, opt.URL), opt.Timeout, opt.Proxy)
@@ -753,7 +757,7 @@ func EurosurveillanceOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML(".pdfItem a.pdf", func(e *colly.HTMLElement) {
+		onHTML(c, ".pdfItem a.pdf", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, "suppdata") {
 				link, _ = RetriveRedirectLink(linkFilter(link, opt.URL), opt.Timeout, opt.Proxy)
@@ -771,8 +775,8 @@ func AerzteblattDeSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://www.aerzteblatt.de")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.aerzteblatt.de"}...)
 	if opt.FullText {
-		c.OnHTML("a.pdfLink", func(e *colly.HTMLElement) {
-			c.OnHTML("div.save a", func(e *colly.HTMLElement) {
+		onHTML(c, "a.pdfLink", func(e *colly.HTMLElement) {
+			onHTML(c, "div.save a", func(e *colly.HTMLElement) {
 				link := e.Attr("href")
 				link = stringo.StrReplaceAll(link, "[?].*", "")
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -791,7 +795,7 @@ func TosOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://tos.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"tos.org"}...)
 	if opt.FullText {
-		c.OnHTML(".large-links-blue a", func(e *colly.HTMLElement) {
+		onHTML(c, ".large-links-blue a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, "docs") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -817,7 +821,7 @@ func AnnalsOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://annals.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"annals.org"}...)
 	if opt.FullText {
-		c.OnHTML("#tagmasterPDF", func(e *colly.HTMLElement) {
+		onHTML(c, "#tagmasterPDF", func(e *colly.HTMLElement) {
 			link := e.Attr("data-article-url")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -832,7 +836,7 @@ func PortlandpressComSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://portlandpress.com")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"portlandpress.com"}...)
 	if opt.FullText {
-		c.OnHTML("a.article-pdfLink", func(e *colly.HTMLElement) {
+		onHTML(c, "a.article-pdfLink", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -847,7 +851,7 @@ func GeoscienceworldOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://pubs.geoscienceworld.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"pubs.geoscienceworld.org"}...)
 	if opt.FullText {
-		c.OnHTML("a.article-pdfLink", func(e *colly.HTMLElement) {
+		onHTML(c, "a.article-pdfLink", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -885,14 +889,14 @@ func AltexOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://www.altex.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.altex.org"}...)
 	if opt.FullText {
-		c.OnHTML(".article-sidebar div.download a.pdf:nth-child(1)", func(e *colly.HTMLElement) {
+		onHTML(c, ".article-sidebar div.download a.pdf:nth-child(1)", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			link = strings.ReplaceAll(link, "/view/", "/download/")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML(".article-sidebar div.download a.pdf:nth-child(2)", func(e *colly.HTMLElement) {
+		onHTML(c, ".article-sidebar div.download a.pdf:nth-child(2)", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			link = strings.ReplaceAll(link, "/view/", "/download/")
 			urls = append(urls, linkFilter(link, opt.URL))
@@ -17,7 +17,7 @@ func ZenodoSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"zenodo.org"}...)
 	if opt.FullText {
-		c.OnHTML("tbody a.filename[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "tbody a.filename[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, "?download=1") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -38,11 +38,11 @@ func CshlpSpider(opt *DoiSpiderOpt) (urls []string) {
 	dataAvail := []string{}
 	if opt.Supplementary {
 		var supplVisited bool
-		c.OnHTML("#supp-adjunct-data a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "#supp-adjunct-data a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
-		c.OnHTML("#mini-panel-biorxiv_art_tools .pane-highwire-variant-link a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "#mini-panel-biorxiv_art_tools .pane-highwire-variant-link a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if !stringo.StrDetect(link, "supplementary-material$|external-links$") {
 				u, _ := url.Parse(link)
@@ -50,27 +50,27 @@ func CshlpSpider(opt *DoiSpiderOpt) (urls []string) {
 				urls = append(urls, link)
 			}
 		})
-		c.OnHTML(".pane-biorxiv-supplementary-fragment a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".pane-biorxiv-supplementary-fragment a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if !stringo.StrDetect(link, "highwire/filestream") && !supplVisited {
 				supplVisited = true
 				Visit(c, linkFilter(link, opt.URL))
 			}
 		})
-		c.OnHTML(".supplementary-material-expansion a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".supplementary-material-expansion a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			link = stringo.StrReplaceAll(link, "[?]download=true$", "")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
-		c.OnHTML("div.auto-clean a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "div.auto-clean a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
-		c.OnHTML("a[rel=supplemental-data]", func(e *colly.HTMLElement) {
+		onHTML(c, "a[rel=supplemental-data]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			Visit(c, linkFilter(link, opt.URL))
 		})
-		c.OnHTML(".data-availability p", func(e *colly.HTMLElement) {
+		onHTML(c, ".data-availability p", func(e *colly.HTMLElement) {
 			text := e.Text
 			dataAvail = append(dataAvail, text)
 		})
@@ -92,13 +92,13 @@ func BiomedcentralSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, BiomedcentralJournalLinks...)
 	if opt.FullText {
-		c.OnHTML(".c-pdf-download a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".c-pdf-download a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML(".c-article-supplementary__item a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".c-article-supplementary__item a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if !stringo.StrDetect(link, "^/articles/") {
 				link = stringo.StrReplaceAll(link, "[?]download=true$", "")
@@ -117,17 +117,17 @@ func PnasSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://www.pnas.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.pnas.org"}...)
 	if opt.FullText {
-		c.OnHTML("a[data-trigger=tab-pdf]", func(e *colly.HTMLElement) {
+		onHTML(c, "a[data-trigger=tab-pdf]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("a['data-trigger'='tab-figures-data']", func(e *colly.HTMLElement) {
+		onHTML(c, "a['data-trigger'='tab-figures-data']", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			Visit(c, link)
 		})
-		c.OnHTML("a.rewritten[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.rewritten[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -148,13 +148,13 @@ func PlosSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"journals.plos.org", "dx.plos.org"}...)
 	if opt.FullText {
-		c.OnHTML("#downloadPdf", func(e *colly.HTMLElement) {
+		onHTML(c, "#downloadPdf", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML(".supplementary-material a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".supplementary-material a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, "doi.org") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -172,13 +172,13 @@ func FrontiersinSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://www.frontiersin.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.frontiersin.org", "journal.frontiersin.org"}...)
 	if opt.FullText {
-		c.OnHTML("a.download-files-pdf", func(e *colly.HTMLElement) {
+		onHTML(c, "a.download-files-pdf", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("a.fs-download-button[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.fs-download-button[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -200,19 +200,19 @@ func PeerjSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://peerj.com")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"peer.com"}...)
 	if opt.FullText {
-		c.OnHTML("a[data-format=PDF]", func(e *colly.HTMLElement) {
+		onHTML(c, "a[data-format=PDF]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Citations {
-		c.OnHTML("a[data-format=BibText]", func(e *colly.HTMLElement) {
+		onHTML(c, "a[data-format=BibText]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("a.article-supporting-download[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.article-supporting-download[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -233,14 +233,14 @@ func OupComSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://academic.oup.com")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"academic.oup.com", "oup.silverchair-cdn.com"}...)
 	if opt.FullText {
-		c.OnHTML("a.article-pdfLink", func(e *colly.HTMLElement) {
+		onHTML(c, "a.article-pdfLink", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			link = "https://academic.oup.com" + link
 			urls = append(urls, link)
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML(".dataSuppLink a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".dataSuppLink a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, link)
 		})
@@ -256,18 +256,18 @@ func EmbopressSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"onlinelibrary.wiley.com", "www.embopress.org"}...)
 	if opt.FullText {
-		c.OnHTML("meta[name=citation_pdf_url]", func(e *colly.HTMLElement) {
+		onHTML(c, "meta[name=citation_pdf_url]", func(e *colly.HTMLElement) {
 			link := e.Attr("content")
 			link = stringo.StrReplaceAll(link, "/doi/pdf/", "/doi/pdfdirect/")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
-		c.OnHTML("div.article-action a[aria-label=PDF]", func(e *colly.HTMLElement) {
+		onHTML(c, "div.article-action a[aria-label=PDF]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML(".article-section__supporting a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".article-section__supporting a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -283,13 +283,13 @@ func AscopubsSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://ascopubs.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"ascopubs.org"}...)
 	if opt.FullText {
-		c.OnHTML(".pdfTools a[download]", func(e *colly.HTMLElement) {
+		onHTML(c, ".pdfTools a[download]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("article.article ul li a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "article.article ul li a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -313,13 +313,13 @@ func HaematologicaSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://www.haematologica.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.haematologica.org"}...)
 	if opt.FullText {
-		c.OnHTML(".pdfTools a[download]", func(e *colly.HTMLElement) {
+		onHTML(c, ".pdfTools a[download]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("article.article ul li a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "article.article ul li a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -345,7 +345,7 @@ func WileyComSpider(opt *DoiSpiderOpt) (urls []string) {
 		"bpspubs.onlinelibrary.wiley.com", "stemcellsjournals.onlinelibrary.wiley.com",
 		"agupubs.onlinelibrary.wiley.com", "www.cochranelibrary.com"}...)
 	if opt.FullText {
-		c.OnHTML("meta[name=citation_pdf_url]", func(e *colly.HTMLElement) {
+		onHTML(c, "meta[name=citation_pdf_url]", func(e *colly.HTMLElement) {
 			link := e.Attr("content")
 			link = stringo.StrReplaceAll(link, "/doi/pdf/", "/doi/pdfdirect/")
 			urls = append(urls, linkFilter(link, opt.URL))
@@ -355,11 +355,11 @@ func WileyComSpider(opt *DoiSpiderOpt) (urls []string) {
 		}
 	}
 	if opt.Supplementary {
-		c.OnHTML(".support-info__table td a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".support-info__table td a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
-		c.OnHTML("a[title='Download full book']", func(e *colly.HTMLElement) {
+		onHTML(c, "a[title='Download full book']", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -375,13 +375,13 @@ func ElifeSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://elifesciences.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"elifesciences.org"}...)
 	if opt.FullText {
-		c.OnHTML("a[data-download-type='pdf-article']", func(e *colly.HTMLElement) {
+		onHTML(c, "a[data-download-type='pdf-article']", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("a.additional-asset__link--download[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.additional-asset__link--download[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -404,7 +404,7 @@ func JciSpider(opt *DoiSpiderOpt) (urls []string) {
 	cnet.SetCollyProxy(c, opt.Proxy, opt.Timeout)
 	extensions.RandomUserAgent(c)
 	if opt.FullText {
-		c.OnHTML("h3 a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "h3 a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, "cloudfront.net") {
 				urls = append(urls, "http:"+link)
@@ -414,7 +414,7 @@ func JciSpider(opt *DoiSpiderOpt) (urls []string) {
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("#supplemental-material a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "#supplemental-material a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			Visit(c, linkFilter(link, opt.URL))
 		})
@@ -435,13 +435,13 @@ func JstatsoftSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://www.jstatsoft.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.jstatsoft.org"}...)
 	if opt.FullText {
-		c.OnHTML("a.file[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.file[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML("a.action[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.action[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -457,7 +457,7 @@ func EjcrimSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://www.ejcrim.com")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.ejcrim.com"}...)
 	if opt.FullText {
-		c.OnHTML("a.pdf[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.pdf[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			link = strings.ReplaceAll(link, "/view/", "/download/")
 			urls = append(urls, link)
@@ -495,7 +495,7 @@ func AutopsyandcasereportsSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "http://autopsyandcasereports.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"autopsyandcasereports.org"}...)
 	if opt.FullText {
-		c.OnHTML("a.pdfType1[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.pdfType1[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -511,7 +511,7 @@ func FigshareSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://figshare.com")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"figshare.com"}...)
 	if opt.FullText {
-		c.OnHTML("a.download-button[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.download-button[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -550,7 +550,7 @@ func PubsRscSpider(opt *DoiSpiderOpt) (urls []string) {
 func AnnualReviewsSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://www.annualreviews.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.annualreviews.org"}...)
-	c.OnHTML(".tool-buttons a.icon-pdf[href]", func(e *colly.HTMLElement) {
+	onHTML(c, ".tool-buttons a.icon-pdf[href]", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		urls = append(urls, linkFilter(link, opt.URL))
 	})
@@ -564,7 +564,7 @@ func MedknowSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, MedknowJournalLinks...)
 	if opt.FullText {
-		c.OnHTML("td p a", func(e *colly.HTMLElement) {
+		onHTML(c, "td p a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, ".pdf") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -572,7 +572,7 @@ func MedknowSpider(opt *DoiSpiderOpt) (urls []string) {
 		})
 		var done bool
 		var done2 bool
-		c.OnHTML("meta[name=citation_pdf_url]", func(e *colly.HTMLElement) {
+		onHTML(c, "meta[name=citation_pdf_url]", func(e *colly.HTMLElement) {
 			link := e.Attr("content")
 			if strings.Contains(link, "article.asp") {
 				if !done {
@@ -581,7 +581,7 @@ func MedknowSpider(opt *DoiSpiderOpt) (urls []string) {
 				done = true
 			}
 		})
-		c.OnHTML("td a:nth-child(2)", func(e *colly.HTMLElement) {
+		onHTML(c, "td a:nth-child(2)", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, "type=2") {
 				if !done2 {
@@ -600,10 +600,10 @@ func MedknowSpider(opt *DoiSpiderOpt) (urls []string) {
 func EajmOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://www.eajm.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"www.eajm.org"}...)
-	c.OnHTML("a.pdf-link", func(e *colly.HTMLElement) {
+	onHTML(c, "a.pdf-link", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		if strings.Contains(link, "/en/") {
-			c.OnHTML("#mainFrame", func(e *colly.HTMLElement) {
+			onHTML(c, "#mainFrame", func(e *colly.HTMLElement) {
 				link := e.Attr("src")
 				urls = append(urls, linkFilter(link, opt.URL))
 			})
@@ -635,7 +635,7 @@ func KoreaMedSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, KoreaMedJournalLinks...)
 	if opt.FullText {
-		c.OnHTML(".portlet-article-body-cell a", func(e *colly.HTMLElement) {
+		onHTML(c, ".portlet-article-body-cell a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, "PDFData") {
 				urls = append(urls, "https://"+hostname+link)
@@ -643,9 +643,9 @@ func KoreaMedSpider(opt *DoiSpiderOpt) (urls []string) {
 		})
 	}
 	if opt.Supplementary {
-		c.OnHTML(".portlet-article-body-cell-supplementary a", func(e *colly.HTMLElement) {
+		onHTML(c, ".portlet-article-body-cell-supplementary a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
-			c.OnHTML(".supplementary-material-item a", func(e *colly.HTMLElement) {
+			onHTML(c, ".supplementary-material-item a", func(e *colly.HTMLElement) {
 				urls = append(urls, "https://"+hostname+"/"+e.Attr("href"))
 			})
 			Visit(c, fmt.Sprintf("%s/%s", hostname, link))
@@ -661,7 +661,7 @@ func Bbk19Spider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://19.bbk.ac.uk")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"19.bbk.ac.uk"}...)
 	if opt.FullText {
-		c.OnHTML(".section ul li:nth-child(2) a:first-child", func(e *colly.HTMLElement) {
+		onHTML(c, ".section ul li:nth-child(2) a:first-child", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			if strings.Contains(link, "/download") {
 				urls = append(urls, linkFilter(link, opt.URL))
@@ -678,7 +678,7 @@ func TheietOrgSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "https://digital-library.theiet.org")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"digital-library.theiet.org"}...)
 	if opt.FullText {
-		c.OnHTML(".headlinebox ul.fulltext li.pdf a:first-child", func(e *colly.HTMLElement) {
+		onHTML(c, ".headlinebox ul.fulltext li.pdf a:first-child", func(e *colly.HTMLElement) {
 			link := "https://digital-library.theiet.org" + e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -12,7 +12,7 @@ func ScihupSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	c.AllowedDomains = append(c.AllowedDomains, []string{"sci-hub.tw"}...)
 	if opt.FullText {
-		c.OnHTML("#buttons a[onclick]", func(e *colly.HTMLElement) {
+		onHTML(c, "#buttons a[onclick]", func(e *colly.HTMLElement) {
 			link := e.Attr("onclick")
 			link = stringo.StrExtract(link, "//.*", 1)[0]
 			link = "http:" + link
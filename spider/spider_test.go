@@ -0,0 +1,81 @@
+package spider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	neturl "net/url"
+	"testing"
+
+	"github.com/openanno/bget/cassette"
+)
+
+func useCassette(t *testing.T, name string) {
+	if err := cassette.Start(cassette.ModeReplay, cassette.Path(name)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNatureComSpider(t *testing.T) {
+	useCassette(t, "nature")
+	defer cassette.Stop()
+	meta := make(map[string]string)
+	u, _ := neturl.Parse("https://www.nature.com/articles/s41586-019-1844-5")
+	opt := &DoiSpiderOpt{
+		Doi:           "10.1038/s41586-019-1844-5",
+		Timeout:       5,
+		FullText:      true,
+		Supplementary: true,
+		CitationMeta:  &meta,
+		URL:           u,
+	}
+	urls := NatureComSpider(opt)
+	expected := []string{
+		"https://www.nature.com/articles/s41586-019-1844-5.pdf",
+		"https://static-content.springer.com/esm/art%3A10.1038%2Fs41586-019-1844-5/MediaObjects/41586_2019_1844_MOESM1_ESM.pdf",
+	}
+	if len(urls) != len(expected) {
+		t.Fatalf("unexpected urls: %v", urls)
+	}
+	for i := range expected {
+		if urls[i] != expected[i] {
+			t.Errorf("unexpected url %d: %s", i, urls[i])
+		}
+	}
+	if meta["citation_doi"] != opt.Doi {
+		t.Errorf("unexpected citation meta: %v", meta)
+	}
+}
+
+func TestGeoSpider(t *testing.T) {
+	useCassette(t, "geo")
+	defer cassette.Stop()
+	gseURLs, gplURLs, sraLink := GeoSpider(&QuerySpiderOpt{Query: "GSE134830", Timeout: 5}, false)
+	if len(gseURLs) != 2 || len(gplURLs) != 0 {
+		t.Errorf("unexpected urls: %v %v", gseURLs, gplURLs)
+	}
+	if sraLink != "https://www.ncbi.nlm.nih.gov/Traces/study/?acc=PRJNA557189" {
+		t.Errorf("unexpected SRA link: %s", sraLink)
+	}
+}
+
+type failTransport struct{ t *testing.T }
+
+func (ft failTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ft.t.Errorf("unexpected request after cancel: %s", req.URL.String())
+	return nil, errors.New("cancelled")
+}
+
+func TestSpiderContext(t *testing.T) {
+	SetTransport(failTransport{t})
+	defer SetTransport(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	meta := make(map[string]string)
+	u, _ := neturl.Parse("https://www.example.org/article/1")
+	opt := &DoiSpiderOpt{Doi: "10.9999/bget.1", Timeout: 5, FullText: true, CitationMeta: &meta,
+		URL: u, Trace: NewSpiderTrace(), Context: ctx}
+	if urls := UniVersalDoiSpider(opt); len(urls) != 0 || len(opt.Trace.Pages) != 0 {
+		t.Errorf("unexpected visits: %v %v", urls, opt.Trace.Pages)
+	}
+}
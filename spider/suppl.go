@@ -0,0 +1,145 @@
+package spider
+
+import (
+	"bytes"
+	neturl "net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly"
+)
+
+// SupplLabel is the label (e.g. Table S1) and caption of a supplementary link
+type SupplLabel struct {
+	Label   string `json:"label,omitempty"`
+	Caption string `json:"caption,omitempty"`
+}
+
+// SupplLabels collects the labels of links on the pages visited by spiders
+type SupplLabels struct {
+	labels map[string]SupplLabel
+	lock   sync.Mutex
+}
+
+var supplLabelPattern = regexp.MustCompile(`(?i)\b((supplementary|supporting|additional|extended)\s+)?(tables?|data|figures?|fig[.]|movies?|videos?|files?|datasets?|appendix|materials?|information|notes?|methods)\s+S?[0-9]+[A-Za-z]?\b`)
+
+var genericLinkText = regexp.MustCompile(`(?i)^(download|pdf|view|open|here|link|file|\(.*\)|[0-9.]+ ?[kmg]?b)$`)
+
+// NewSupplLabels return an empty SupplLabels
+func NewSupplLabels() *SupplLabels {
+	return &SupplLabels{labels: make(map[string]SupplLabel)}
+}
+
+// Get return the label of link, links are matched by URL and then by path
+func (labels *SupplLabels) Get(link string) SupplLabel {
+	if labels == nil {
+		return SupplLabel{}
+	}
+	labels.lock.Lock()
+	defer labels.lock.Unlock()
+	if v, ok := labels.labels[link]; ok {
+		return v
+	}
+	u, err := neturl.Parse(link)
+	if err != nil || u.Path == "" || u.Path == "/" {
+		return SupplLabel{}
+	}
+	for k, v := range labels.labels {
+		if ku, err := neturl.Parse(k); err == nil && ku.Path == u.Path && ku.RawQuery == u.RawQuery {
+			return v
+		}
+	}
+	return SupplLabel{}
+}
+
+func (labels *SupplLabels) attach(c *colly.Collector) {
+	c.OnResponse(func(r *colly.Response) {
+		if !strings.Contains(strings.ToLower(r.Headers.Get("Content-Type")), "html") {
+			return
+		}
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(r.Body))
+		if err != nil {
+			return
+		}
+		found := ExtractSupplLabels(doc, r.Request.URL)
+		labels.lock.Lock()
+		defer labels.lock.Unlock()
+		for k, v := range found {
+			if _, ok := labels.labels[k]; !ok {
+				labels.labels[k] = v
+			}
+		}
+	})
+}
+
+// ExtractSupplLabels return the labels and captions of the links in doc,
+// the keys are absolute URLs
+func ExtractSupplLabels(doc *goquery.Document, base *neturl.URL) map[string]SupplLabel {
+	ret := make(map[string]SupplLabel)
+	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript") {
+			return
+		}
+		link := href
+		if base != nil {
+			if u, err := base.Parse(href); err == nil {
+				link = u.String()
+			}
+		}
+		if label := supplLabel(s); label.Label != "" || label.Caption != "" {
+			ret[link] = label
+		}
+	})
+	return ret
+}
+
+func supplLabel(s *goquery.Selection) (label SupplLabel) {
+	text := selectionText(s)
+	title, _ := s.Attr("title")
+	aria, _ := s.Attr("aria-label")
+	texts := []string{text, collapseSpace(title), collapseSpace(aria)}
+	// the caption is the text of the closest ancestors holding only this link
+	parent := s.Parent()
+	for i := 0; i < 3 && parent.Length() > 0 && parent.Find("a[href]").Length() == 1; i++ {
+		if t := selectionText(parent); len(t) <= 500 {
+			texts = append(texts, t)
+			if label.Caption == "" && t != text && len(t) > len(text) {
+				label.Caption = t
+			}
+		}
+		parent = parent.Parent()
+	}
+	for _, t := range texts {
+		if m := supplLabelPattern.FindString(t); m != "" {
+			label.Label = m
+			break
+		}
+	}
+	if label.Label == "" && text != "" && len(text) <= 200 && !genericLinkText.MatchString(text) {
+		label.Label = text
+	}
+	if r := []rune(label.Caption); len(r) > 300 {
+		label.Caption = string(r[:300])
+	}
+	return label
+}
+
+// selectionText return the text of s, the text of child nodes are separated by spaces
+func selectionText(s *goquery.Selection) string {
+	var texts []string
+	s.Contents().Each(func(i int, c *goquery.Selection) {
+		if goquery.NodeName(c) == "#text" {
+			texts = append(texts, c.Text())
+		} else {
+			texts = append(texts, selectionText(c))
+		}
+	})
+	return collapseSpace(strings.Join(texts, " "))
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
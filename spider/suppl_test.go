@@ -0,0 +1,46 @@
+package spider
+
+import (
+	neturl "net/url"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestExtractSupplLabels(t *testing.T) {
+	html := `<html><body>
+<div class="suppl"><h3>Supplementary Table 1</h3><p>Differentially expressed genes.</p>
+  <a href="/esm/mmc1.xlsx">Download (120 KB)</a></div>
+<ul><li><a href="https://cdn.org/data_s2.csv">Data S2. Raw counts of all samples</a></li></ul>
+<a href="#top">Top</a>
+</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := neturl.Parse("https://www.cell.com/article/1")
+	labels := ExtractSupplLabels(doc, base)
+	if v := labels["https://www.cell.com/esm/mmc1.xlsx"]; v.Label != "Supplementary Table 1" || !strings.Contains(v.Caption, "Differentially expressed") {
+		t.Errorf("unexpected label: %+v", v)
+	}
+	if v := labels["https://cdn.org/data_s2.csv"]; v.Label != "Data S2" {
+		t.Errorf("unexpected label: %+v", v)
+	}
+	if _, ok := labels["https://www.cell.com/article/1#top"]; ok {
+		t.Error("anchors should be skipped")
+	}
+}
+
+func TestSupplLabelCaption(t *testing.T) {
+	caption := strings.Repeat("a", 290) + strings.Repeat("é", 20)
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div><p>` + caption + `</p><a href="/s1.pdf">Table S1</a></div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := supplLabel(doc.Find("a"))
+	if !utf8.ValidString(v.Caption) || utf8.RuneCountInString(v.Caption) != 300 {
+		t.Errorf("unexpected caption: %q", v.Caption)
+	}
+}
@@ -0,0 +1,67 @@
+package spider
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gocolly/colly"
+)
+
+var transport http.RoundTripper
+
+// SetTransport set the http.RoundTripper used by all collectors,
+// nil restores the default transport
+func SetTransport(rt http.RoundTripper) {
+	transport = rt
+}
+
+// SpiderTrace records the pages visited and the HTML selectors matched by a spider
+type SpiderTrace struct {
+	Pages     []TracePage    `json:"pages"`
+	Selectors map[string]int `json:"selectors"`
+	lock      sync.Mutex
+}
+
+// TracePage is one page visited by a spider
+type TracePage struct {
+	URL    string `json:"url"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewSpiderTrace return an empty SpiderTrace
+func NewSpiderTrace() *SpiderTrace {
+	return &SpiderTrace{Selectors: make(map[string]int)}
+}
+
+// traceKey is the key of the SpiderTrace in the colly request context
+const traceKey = "bget_trace"
+
+func (trace *SpiderTrace) attach(c *colly.Collector) {
+	c.OnRequest(func(r *colly.Request) {
+		r.Ctx.Put(traceKey, trace)
+	})
+	c.OnResponse(func(r *colly.Response) {
+		trace.lock.Lock()
+		defer trace.lock.Unlock()
+		trace.Pages = append(trace.Pages, TracePage{URL: r.Request.URL.String(), Status: r.StatusCode})
+	})
+	c.OnError(func(r *colly.Response, err error) {
+		trace.lock.Lock()
+		defer trace.lock.Unlock()
+		trace.Pages = append(trace.Pages, TracePage{URL: r.Request.URL.String(), Status: r.StatusCode, Error: err.Error()})
+	})
+}
+
+// onHTML register f for selector on c, the matched elements are counted
+// in the SpiderTrace of the request if any
+func onHTML(c *colly.Collector, selector string, f colly.HTMLCallback) {
+	c.OnHTML(selector, func(e *colly.HTMLElement) {
+		if trace, ok := e.Request.Ctx.GetAny(traceKey).(*SpiderTrace); ok {
+			trace.lock.Lock()
+			trace.Selectors[selector]++
+			trace.lock.Unlock()
+		}
+		f(e)
+	})
+}
@@ -1,6 +1,9 @@
 package spider
 
-import neturl "net/url"
+import (
+	"context"
+	neturl "net/url"
+)
 
 type DoiSpiderOpt struct {
 	Doi               string
@@ -13,6 +16,15 @@ type DoiSpiderOpt struct {
 	PrintCrossRefMeta bool
 	CitationMeta      *map[string]string
 	URL               *neturl.URL
+	// Trace records the visited pages and matched selectors if not nil
+	Trace *SpiderTrace
+	// SupplLabels collects the labels of supplementary links if not nil
+	SupplLabels *SupplLabels
+	// PID is the scheme of non-DOI identifiers (handle, ark, purl or curie),
+	// the spiders visit the resolved URL instead of doi.org
+	PID string
+	// Context stops the requests of the spider once it is done if not nil
+	Context context.Context
 }
 type QuerySpiderOpt struct {
 	Query   string
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gocolly/colly"
 	"github.com/gocolly/colly/extensions"
+	"github.com/openanno/bget/cassette"
 	cnet "github.com/openbiox/ligo/net"
 	"github.com/openbiox/ligo/slice"
 	"github.com/openbiox/ligo/stringo"
@@ -28,17 +29,17 @@ func UniVersalDoiSpider(opt *DoiSpiderOpt) (urls []string) {
 		}
 	}
 	if opt.Supplementary {
-		c.OnHTML("a.rewritten[href]", func(e *colly.HTMLElement) {
+		onHTML(c, "a.rewritten[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
 		// https://www.microbiologyresearch.org/ specific
-		c.OnHTML("#supplementary_data form.js-ft-download-form[action]", func(e *colly.HTMLElement) {
+		onHTML(c, "#supplementary_data form.js-ft-download-form[action]", func(e *colly.HTMLElement) {
 			link := e.Attr("action")
 			link = stringo.StrReplaceAll(link, "[?].*", "")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
-		c.OnHTML("#SuppDataIndexList .textoptionsFulltext ul.fulltext li a", func(e *colly.HTMLElement) {
+		onHTML(c, "#SuppDataIndexList .textoptionsFulltext ul.fulltext li a", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			urls = append(urls, linkFilter(link, opt.URL))
 		})
@@ -58,6 +59,10 @@ func UniVersalDoiSpider(opt *DoiSpiderOpt) (urls []string) {
 }
 
 func visitFilter(c *colly.Collector, opt *DoiSpiderOpt) {
+	if opt.PID != "" && opt.URL != nil {
+		Visit(c, opt.URL.String())
+		return
+	}
 	dxDoiSites := []string{"10.1561/", "10.15585/"}
 	dxsites := false
 	for _, v := range dxDoiSites {
@@ -75,7 +80,7 @@ func visitFilter(c *colly.Collector, opt *DoiSpiderOpt) {
 }
 
 func UniVersalDoiSpiderListenPart1(c *colly.Collector, opt *DoiSpiderOpt, urls *[]string) {
-	c.OnHTML("meta[title='Full Text (PDF)']", func(e *colly.HTMLElement) {
+	onHTML(c, "meta[title='Full Text (PDF)']", func(e *colly.HTMLElement) {
 		link := e.Attr("content")
 		*urls = append(*urls, linkFilter(link, opt.URL))
 	})
@@ -93,48 +98,48 @@ func UniVersalDoiSpiderListenPart2(c *colly.Collector, opt *DoiSpiderOpt, urls *
 		".intent_pdf_link", ".icon-102_download_pdf a.download", ".download-and-link ul.additional_info a.triangle",
 		".page-sidebar a.download-pdf"}
 	for _, v := range hrefPattern {
-		c.OnHTML(v, func(e *colly.HTMLElement) {
+		onHTML(c, v, func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			*urls = append(*urls, linkFilter(link, opt.URL))
 		})
 	}
 }
 func UniVersalDoiSpiderListenPart3(c *colly.Collector, opt *DoiSpiderOpt, urls *[]string) {
-	c.OnHTML("a.article-pdfLink[data-article-url]", func(e *colly.HTMLElement) {
+	onHTML(c, "a.article-pdfLink[data-article-url]", func(e *colly.HTMLElement) {
 		link := e.Attr("data-article-url")
 		*urls = append(*urls, linkFilter(link, opt.URL))
 	})
-	c.OnHTML("iframe.pdf[data-src]", func(e *colly.HTMLElement) {
+	onHTML(c, "iframe.pdf[data-src]", func(e *colly.HTMLElement) {
 		link := e.Attr("data-src")
 		*urls = append(*urls, linkFilter(link, opt.URL))
 	})
-	c.OnHTML("td b a[target='_blank']", func(e *colly.HTMLElement) {
+	onHTML(c, "td b a[target='_blank']", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		if strings.Contains(link, "pdf.php") {
 			*urls = append(*urls, linkFilter(link, opt.URL))
 		}
 	})
-	c.OnHTML("ul.galleys_links li a.obj_galley_link", func(e *colly.HTMLElement) {
+	onHTML(c, "ul.galleys_links li a.obj_galley_link", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
-		c.OnHTML("a.download", func(e *colly.HTMLElement) {
+		onHTML(c, "a.download", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			*urls = append(*urls, linkFilter(link, opt.URL))
 		})
 		Visit(c, linkFilter(link, opt.URL))
 	})
-	c.OnHTML(".article-content table td a:first-child", func(e *colly.HTMLElement) {
+	onHTML(c, ".article-content table td a:first-child", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		if strings.Contains(link, ".pdf") {
 			*urls = append(*urls, linkFilter(link, opt.URL))
 		}
 	})
-	c.OnHTML("#articleFullText a", func(e *colly.HTMLElement) {
+	onHTML(c, "#articleFullText a", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		if strings.Contains(link, "/download/") {
 			*urls = append(*urls, linkFilter(link, opt.URL))
 		}
 	})
-	c.OnHTML("a.pdf[data-popup]", func(e *colly.HTMLElement) {
+	onHTML(c, "a.pdf[data-popup]", func(e *colly.HTMLElement) {
 		link := e.Attr("data-popup")
 		if strings.Contains(link, "/search/") {
 			*urls = append(*urls, linkFilter(link, opt.URL))
@@ -143,24 +148,24 @@ func UniVersalDoiSpiderListenPart3(c *colly.Collector, opt *DoiSpiderOpt, urls *
 }
 
 func UniVersalDoiSpiderListenPart4(c *colly.Collector, opt *DoiSpiderOpt, urls *[]string) {
-	c.OnHTML("td.auto-style21 a.auto-style15", func(e *colly.HTMLElement) {
+	onHTML(c, "td.auto-style21 a.auto-style15", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		link = stringo.StrReplaceAll(link, "^../../", "/")
 		*urls = append(*urls, linkFilter(link, opt.URL))
 	})
-	c.OnHTML("frameset frame:first-child", func(e *colly.HTMLElement) {
+	onHTML(c, "frameset frame:first-child", func(e *colly.HTMLElement) {
 		link := e.Attr("src")
 		if strings.Contains(link, "saje/article") {
 			link = stringo.StrReplaceAll(link, "viewPDFInterstitial", "viewFile")
 			*urls = append(*urls, linkFilter(link, opt.URL))
 		}
 	})
-	c.OnHTML("#content p a.linkintext", func(e *colly.HTMLElement) {
+	onHTML(c, "#content p a.linkintext", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		link = stringo.StrReplaceAll(link, "^../../", "/")
 		*urls = append(*urls, linkFilter(link, opt.URL))
 	})
-	c.OnHTML("div.pull-right a.btn-galley", func(e *colly.HTMLElement) {
+	onHTML(c, "div.pull-right a.btn-galley", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		if strings.Contains(link, "/view/") {
 			link = strings.ReplaceAll(link, "/view/", "/download/")
@@ -168,12 +173,12 @@ func UniVersalDoiSpiderListenPart4(c *colly.Collector, opt *DoiSpiderOpt, urls *
 		}
 	})
 	// https://www.microbiologyresearch.org/ specific
-	c.OnHTML("form.ft-download-content__form--pdf[action]", func(e *colly.HTMLElement) {
+	onHTML(c, "form.ft-download-content__form--pdf[action]", func(e *colly.HTMLElement) {
 		link := e.Attr("action")
 		link = stringo.StrReplaceAll(link, "pdf[?].*", "pdf")
 		*urls = append(*urls, linkFilter(link, opt.URL))
 	})
-	c.OnHTML("a[title='Article permanent link']", func(e *colly.HTMLElement) {
+	onHTML(c, "a[title='Article permanent link']", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 		link = stringo.StrReplaceAll(link, "full", "")
 		link = link + "pdf/" + path.Base(link) + ".pdf"
@@ -253,7 +258,7 @@ func AddPdfplusWithSupplSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	urls = AddPdfplusSpider(opt)
 	if opt.Supplementary {
-		c.OnHTML(".suppl_list a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".suppl_list a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			link = "https://" + opt.URL.Hostname() + link
 			urls = append(urls, link)
@@ -269,7 +274,7 @@ func AddPdfWithSupplSpider(opt *DoiSpiderOpt) (urls []string) {
 	c := initDoiColley(opt, "")
 	urls = AddPdfSpider(opt)
 	if opt.Supplementary {
-		c.OnHTML(".suppl_list a[href]", func(e *colly.HTMLElement) {
+		onHTML(c, ".suppl_list a[href]", func(e *colly.HTMLElement) {
 			link := e.Attr("href")
 			link = "https://" + opt.URL.Hostname() + link
 			urls = append(urls, link)
@@ -313,7 +318,7 @@ func ReplaceHtmlSpider(opt *DoiSpiderOpt) (urls []string) {
 }
 
 func RetriveRedirectLink(url string, timeout int, proxy string) (string, error) {
-	client := cnet.NewHTTPClient(timeout, proxy)
+	client := cassette.NewHTTPClient(timeout, proxy)
 	req, _ := http.NewRequest("HEAD", url, nil)
 	resp, err := client.Do(req)
 	if err != nil {
@@ -371,11 +376,24 @@ func initDoiColley(opt *DoiSpiderOpt, host string) *colly.Collector {
 	if opt.URL != nil {
 		c.AllowedDomains = append(c.AllowedDomains, opt.URL.Host)
 	}
-	c.OnHTML("meta[title='Full Text (PDF)']", func(e *colly.HTMLElement) {
+	if opt.Context != nil {
+		c.OnRequest(func(r *colly.Request) {
+			if opt.Context.Err() != nil {
+				r.Abort()
+			}
+		})
+	}
+	if opt.Trace != nil {
+		opt.Trace.attach(c)
+	}
+	if opt.Supplementary && opt.SupplLabels != nil {
+		opt.SupplLabels.attach(c)
+	}
+	onHTML(c, "meta[title='Full Text (PDF)']", func(e *colly.HTMLElement) {
 		link := e.Attr("content")
 		(*opt.CitationMeta)["citation_pdf_url"] = linkFilter(link, opt.URL)
 	})
-	c.OnHTML("meta[name]", func(e *colly.HTMLElement) {
+	onHTML(c, "meta[name]", func(e *colly.HTMLElement) {
 		content := e.Attr("content")
 		name := e.Attr("name")
 		if _, ok := (*opt.CitationMeta)[name]; ok && content != "" && (*opt.CitationMeta)[name] != "" &&
@@ -399,6 +417,11 @@ func initBasicColley(timeout int, proxy string) *colly.Collector {
 	)
 	c.AllowedDomains = append(c.AllowedDomains, UniversalJournalLinks...)
 	cnet.SetCollyProxy(c, proxy, timeout)
+	if transport != nil {
+		c.WithTransport(transport)
+	} else if cassette.Enabled() {
+		c.WithTransport(cassette.NewHTTPClient(timeout, proxy).Transport)
+	}
 	extensions.RandomUserAgent(c)
 	extensions.Referer(c)
 	c.OnRequest(func(r *colly.Request) {